@@ -4,7 +4,9 @@ import (
 	"analytics-service/handler"
 	"analytics-service/metrics"
 	"analytics-service/middleware"
+	"analytics-service/personalize"
 	"log"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -30,6 +32,10 @@ func StartServer(db *mongo.Database) {
 
 	// Create handlers
 	analyticsHandler := handler.NewAnalyticsHandler(db)
+	personalizeHandler := handler.NewPersonalizeHandler(
+		personalize.NewProfileBuilder(db, personalize.DefaultConfig()),
+		24*time.Hour,
+	)
 
 	// Metrics endpoint for Prometheus scraping
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -47,6 +53,8 @@ func StartServer(db *mongo.Database) {
 	{
 		api.POST("/analytics/track", analyticsHandler.TrackEvent)
 		api.GET("/analytics/stats", analyticsHandler.GetStats)
+		api.GET("/personalize/profile/:session_id", personalizeHandler.GetProfile)
+		api.POST("/personalize/rank", personalizeHandler.Rank)
 	}
 
 	// Analytics endpoints with ingress prefix (/analytics-api maps to service root)
@@ -54,6 +62,8 @@ func StartServer(db *mongo.Database) {
 	{
 		analyticsAPI.POST("/analytics/track", analyticsHandler.TrackEvent)
 		analyticsAPI.GET("/analytics/stats", analyticsHandler.GetStats)
+		analyticsAPI.GET("/personalize/profile/:session_id", personalizeHandler.GetProfile)
+		analyticsAPI.POST("/personalize/rank", personalizeHandler.Rank)
 	}
 
 	log.Println("Analytics service starting on port 8080...")
@@ -101,14 +101,19 @@ func (h *AnalyticsHandler) recordVisit(c *gin.Context, req model.AnalyticsReques
 
 func (h *AnalyticsHandler) recordPageView(c *gin.Context, req model.AnalyticsRequest) {
 	pageView := model.PageView{
-		SessionID:   req.SessionID,
-		Page:        req.Page,
-		Title:       req.Title,
-		URL:         req.URL,
-		Timestamp:   time.Now(),
-		TimeOnPage:  req.TimeOnPage,
-		ScrollDepth: req.ScrollDepth,
-		ExitPage:    false,
+		SessionID:       req.SessionID,
+		Page:            req.Page,
+		Title:           req.Title,
+		URL:             req.URL,
+		Timestamp:       time.Now(),
+		TimeOnPage:      req.TimeOnPage,
+		ScrollDepth:     req.ScrollDepth,
+		ExitPage:        false,
+		ContentType:     req.ContentType,
+		ContentID:       req.ContentID,
+		ContentRegion:   req.ContentRegion,
+		ContentSource:   req.ContentSource,
+		ContentCategory: req.ContentCategory,
 	}
 
 	collection := h.db.Collection("pageviews")
@@ -139,14 +144,19 @@ func (h *AnalyticsHandler) recordExit(c *gin.Context, req model.AnalyticsRequest
 	if err != nil {
 		// If no existing page view found, create one
 		pageView := model.PageView{
-			SessionID:   req.SessionID,
-			Page:        req.Page,
-			Title:       req.Title,
-			URL:         req.URL,
-			Timestamp:   time.Now(),
-			TimeOnPage:  req.TimeOnPage,
-			ScrollDepth: req.ScrollDepth,
-			ExitPage:    true,
+			SessionID:       req.SessionID,
+			Page:            req.Page,
+			Title:           req.Title,
+			URL:             req.URL,
+			Timestamp:       time.Now(),
+			TimeOnPage:      req.TimeOnPage,
+			ScrollDepth:     req.ScrollDepth,
+			ExitPage:        true,
+			ContentType:     req.ContentType,
+			ContentID:       req.ContentID,
+			ContentRegion:   req.ContentRegion,
+			ContentSource:   req.ContentSource,
+			ContentCategory: req.ContentCategory,
 		}
 		collection.InsertOne(context.Background(), pageView)
 	}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"analytics-service/personalize"
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRankCandidates bounds the work a single rank request can ask for
+const maxRankCandidates = 500
+
+type PersonalizeHandler struct {
+	profiles *personalize.ProfileBuilder
+	halfLife time.Duration
+}
+
+func NewPersonalizeHandler(profiles *personalize.ProfileBuilder, halfLife time.Duration) *PersonalizeHandler {
+	return &PersonalizeHandler{profiles: profiles, halfLife: halfLife}
+}
+
+// RankRequest is sent by content services to order a result set for a session
+type RankRequest struct {
+	SessionID  string                  `json:"session_id" binding:"required"`
+	Candidates []personalize.Candidate `json:"candidates" binding:"required,dive"`
+	Weights    *personalize.Weights    `json:"weights"`
+}
+
+// GetProfile returns the interest profile for a session
+func (h *PersonalizeHandler) GetProfile(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	profile, err := h.profiles.Profile(ctx, c.Param("session_id"))
+	if err != nil {
+		log.Printf("Failed to build profile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Rank orders candidates by recency, popularity and session affinity
+func (h *PersonalizeHandler) Rank(c *gin.Context) {
+	var req RankRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Candidates) > maxRankCandidates {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many candidates"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	profile, err := h.profiles.Profile(ctx, req.SessionID)
+	if err != nil {
+		log.Printf("Failed to build profile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build profile"})
+		return
+	}
+
+	weights := personalize.DefaultWeights
+	if req.Weights != nil {
+		weights = *req.Weights
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id":   req.SessionID,
+		"personalized": !profile.Empty(),
+		"views":        profile.Views,
+		"rankings":     personalize.Rank(profile, req.Candidates, weights, h.halfLife),
+	})
+}
@@ -52,6 +52,12 @@ type PageView struct {
 	TimeOnPage  int64              `bson:"time_on_page,omitempty" json:"time_on_page,omitempty"` // seconds
 	ScrollDepth float64            `bson:"scroll_depth,omitempty" json:"scroll_depth,omitempty"` // percentage
 	ExitPage    bool               `bson:"exit_page" json:"exit_page"`
+	// Content the page showed, when the frontend reports it; feeds personalisation
+	ContentType     string `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	ContentID       string `bson:"content_id,omitempty" json:"content_id,omitempty"`
+	ContentRegion   string `bson:"content_region,omitempty" json:"content_region,omitempty"`
+	ContentSource   string `bson:"content_source,omitempty" json:"content_source,omitempty"`
+	ContentCategory string `bson:"content_category,omitempty" json:"content_category,omitempty"`
 }
 
 // AnalyticsRequest represents the incoming analytics data from frontend
@@ -81,6 +87,12 @@ type AnalyticsRequest struct {
 	PixelRatio            float64 `json:"pixel_ratio"`
 	AvailableScreenWidth  int     `json:"available_screen_width"`
 	AvailableScreenHeight int     `json:"available_screen_height"`
+	// Optional content metadata for article, video or story pages
+	ContentType     string `json:"content_type"`
+	ContentID       string `json:"content_id"`
+	ContentRegion   string `json:"content_region"`
+	ContentSource   string `json:"content_source"`
+	ContentCategory string `json:"content_category"`
 }
 
 // AnalyticsStats represents aggregated analytics data
@@ -0,0 +1,257 @@
+package personalize
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"analytics-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Profile is a session's interest in each feature, normalised so the
+// strongest value per dimension is 1
+type Profile struct {
+	SessionID  string             `json:"session_id"`
+	Views      int                `json:"views"`
+	Regions    map[string]float64 `json:"regions"`
+	Sources    map[string]float64 `json:"sources"`
+	Categories map[string]float64 `json:"categories"`
+	Keywords   map[string]float64 `json:"keywords"`
+	BuiltAt    time.Time          `json:"built_at"`
+}
+
+// Empty reports whether the session has no usable history
+func (p *Profile) Empty() bool {
+	return p == nil || p.Views == 0
+}
+
+// Config controls how much history feeds a profile
+type Config struct {
+	Lookback    time.Duration // Oldest page view considered
+	HalfLife    time.Duration // Age at which a view counts half
+	MaxViews    int64         // Most recent views read per session
+	MaxKeywords int           // Keywords kept after ranking
+	CacheTTL    time.Duration // How long a built profile is reused
+}
+
+// DefaultConfig returns the settings used in production
+func DefaultConfig() Config {
+	return Config{
+		Lookback:    7 * 24 * time.Hour,
+		HalfLife:    48 * time.Hour,
+		MaxViews:    500,
+		MaxKeywords: 25,
+		CacheTTL:    time.Minute,
+	}
+}
+
+// ProfileBuilder builds session profiles from the pageviews collection
+type ProfileBuilder struct {
+	pageviews *mongo.Collection
+	config    Config
+
+	mu    sync.Mutex
+	cache map[string]*Profile
+}
+
+// NewProfileBuilder creates a builder and the index it queries by
+func NewProfileBuilder(db *mongo.Database, config Config) *ProfileBuilder {
+	b := &ProfileBuilder{
+		pageviews: db.Collection("pageviews"),
+		config:    config,
+		cache:     make(map[string]*Profile),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := b.pageviews.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "session_id", Value: 1}, {Key: "timestamp", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to create pageviews session index: %v", err)
+	}
+
+	return b
+}
+
+// Profile returns the cached profile for sessionID, rebuilding it when stale
+func (b *ProfileBuilder) Profile(ctx context.Context, sessionID string) (*Profile, error) {
+	b.mu.Lock()
+	cached, ok := b.cache[sessionID]
+	b.mu.Unlock()
+	if ok && time.Since(cached.BuiltAt) < b.config.CacheTTL {
+		return cached, nil
+	}
+
+	profile, err := b.build(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.cache[sessionID] = profile
+	// Drop expired entries so abandoned sessions don't accumulate
+	for id, p := range b.cache {
+		if time.Since(p.BuiltAt) >= b.config.CacheTTL {
+			delete(b.cache, id)
+		}
+	}
+	b.mu.Unlock()
+
+	return profile, nil
+}
+
+func (b *ProfileBuilder) build(ctx context.Context, sessionID string) (*Profile, error) {
+	now := time.Now()
+	filter := bson.M{
+		"session_id": sessionID,
+		"timestamp":  bson.M{"$gte": now.Add(-b.config.Lookback)},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(b.config.MaxViews)
+
+	cursor, err := b.pageviews.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query page views: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var views []model.PageView
+	if err := cursor.All(ctx, &views); err != nil {
+		return nil, fmt.Errorf("failed to decode page views: %w", err)
+	}
+
+	profile := &Profile{
+		SessionID:  sessionID,
+		Views:      len(views),
+		Regions:    make(map[string]float64),
+		Sources:    make(map[string]float64),
+		Categories: make(map[string]float64),
+		Keywords:   make(map[string]float64),
+		BuiltAt:    now,
+	}
+
+	for _, view := range views {
+		weight := engagement(view) * decay(now.Sub(view.Timestamp), b.config.HalfLife)
+
+		region, category := view.ContentRegion, view.ContentCategory
+		if parsed, err := url.Parse(view.URL); err == nil {
+			query := parsed.Query()
+			if region == "" {
+				region = query.Get("region")
+			}
+			if category == "" {
+				category = query.Get("category")
+			}
+		}
+
+		add(profile.Regions, region, weight)
+		add(profile.Sources, view.ContentSource, weight)
+		add(profile.Categories, category, weight)
+		for _, token := range Tokenize(view.Title) {
+			add(profile.Keywords, token, weight)
+		}
+	}
+
+	profile.Keywords = topN(profile.Keywords, b.config.MaxKeywords)
+	for _, dim := range []map[string]float64{profile.Regions, profile.Sources, profile.Categories, profile.Keywords} {
+		normalise(dim)
+	}
+	return profile, nil
+}
+
+// engagement weighs a view by how long and how far the reader stayed.
+// A bounce counts 1; five minutes with a full scroll counts 3.
+func engagement(view model.PageView) float64 {
+	seconds := math.Min(float64(view.TimeOnPage), 300)
+	depth := math.Max(0, math.Min(view.ScrollDepth, 100))
+	return 1 + seconds/300 + depth/100
+}
+
+func decay(age, halfLife time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+	return math.Exp(-math.Ln2 * age.Hours() / halfLife.Hours())
+}
+
+func add(dim map[string]float64, key string, weight float64) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key != "" {
+		dim[key] += weight
+	}
+}
+
+func normalise(dim map[string]float64) {
+	max := 0.0
+	for _, v := range dim {
+		max = math.Max(max, v)
+	}
+	if max == 0 {
+		return
+	}
+	for k, v := range dim {
+		dim[k] = v / max
+	}
+}
+
+func topN(dim map[string]float64, n int) map[string]float64 {
+	if len(dim) <= n {
+		return dim
+	}
+	keys := make([]string, 0, len(dim))
+	for k := range dim {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if dim[keys[i]] != dim[keys[j]] {
+			return dim[keys[i]] > dim[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	result := make(map[string]float64, n)
+	for _, k := range keys[:n] {
+		result[k] = dim[k]
+	}
+	return result
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "that": true,
+	"this": true, "are": true, "was": true, "were": true, "has": true, "have": true,
+	"not": true, "but": true, "you": true, "your": true, "its": true, "our": true,
+	"into": true, "over": true, "after": true, "about": true, "what": true, "how": true,
+	"why": true, "who": true, "will": true, "can": true, "new": true, "news": true,
+	"says": true, "said": true, "more": true, "than": true, "out": true, "all": true,
+	"video": true, "videos": true, "trending": true, "viral": true, "memes": true,
+}
+
+// Tokenize lowercases text and returns its content words
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 3 || stopWords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
@@ -0,0 +1,139 @@
+package personalize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Candidate is an item a content service wants ranked for a session
+type Candidate struct {
+	ID          string    `json:"id" binding:"required"`
+	Title       string    `json:"title"`
+	Region      string    `json:"region"`
+	Source      string    `json:"source"`
+	Category    string    `json:"category"`
+	PublishedAt time.Time `json:"published_at"`
+	Popularity  float64   `json:"popularity"` // Service-specific score normalised to 0..1
+}
+
+// Weights blends the three ranking components
+type Weights struct {
+	Recency    float64 `json:"recency"`
+	Popularity float64 `json:"popularity"`
+	Affinity   float64 `json:"affinity"`
+}
+
+// DefaultWeights is used when the caller doesn't supply its own
+var DefaultWeights = Weights{Recency: 0.35, Popularity: 0.25, Affinity: 0.4}
+
+// Affinity is split across profile dimensions
+var dimensionWeights = struct {
+	Region, Source, Category, Keywords float64
+}{0.2, 0.25, 0.25, 0.3}
+
+// Ranking is a candidate's position with the reasoning behind it
+type Ranking struct {
+	ID          string      `json:"id"`
+	Score       float64     `json:"score"`
+	Explanation Explanation `json:"explanation"`
+}
+
+// Explanation breaks a score into its components for debugging
+type Explanation struct {
+	Recency    float64  `json:"recency"`
+	Popularity float64  `json:"popularity"`
+	Affinity   float64  `json:"affinity"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// Rank scores candidates against profile and returns them best first.
+// With an empty profile affinity is dropped and the other weights rescaled,
+// so new sessions get a plain recency/popularity ordering.
+func Rank(profile *Profile, candidates []Candidate, weights Weights, halfLife time.Duration) []Ranking {
+	if profile.Empty() {
+		weights.Affinity = 0
+	}
+	total := weights.Recency + weights.Popularity + weights.Affinity
+	if total <= 0 {
+		weights, total = DefaultWeights, 1
+	}
+
+	now := time.Now()
+	rankings := make([]Ranking, len(candidates))
+	for i, candidate := range candidates {
+		explanation := Explanation{
+			Recency:    recency(candidate.PublishedAt, now, halfLife),
+			Popularity: math.Max(0, math.Min(1, candidate.Popularity)),
+		}
+		if !profile.Empty() {
+			explanation.Affinity, explanation.Reasons = affinity(profile, candidate)
+		}
+
+		score := (weights.Recency*explanation.Recency +
+			weights.Popularity*explanation.Popularity +
+			weights.Affinity*explanation.Affinity) / total
+
+		rankings[i] = Ranking{ID: candidate.ID, Score: score, Explanation: explanation}
+	}
+
+	// Stable so equal scores keep the caller's order
+	sort.SliceStable(rankings, func(i, j int) bool {
+		return rankings[i].Score > rankings[j].Score
+	})
+	return rankings
+}
+
+func recency(publishedAt, now time.Time, halfLife time.Duration) float64 {
+	if publishedAt.IsZero() {
+		return 0.5
+	}
+	return decay(now.Sub(publishedAt), halfLife)
+}
+
+// affinity scores how well a candidate matches the profile and records
+// which features contributed
+func affinity(profile *Profile, candidate Candidate) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	match := func(dim map[string]float64, label, value string, weight float64) {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if v := dim[value]; value != "" && v > 0 {
+			score += weight * v
+			reasons = append(reasons, fmt.Sprintf("%s:%s (%.2f)", label, value, v))
+		}
+	}
+	match(profile.Regions, "region", candidate.Region, dimensionWeights.Region)
+	match(profile.Sources, "source", candidate.Source, dimensionWeights.Source)
+	match(profile.Categories, "category", candidate.Category, dimensionWeights.Category)
+
+	// Keyword affinity is the mean of the best matches so long titles don't win by length
+	var matched []float64
+	seen := make(map[string]bool)
+	for _, token := range Tokenize(candidate.Title) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if v := profile.Keywords[token]; v > 0 {
+			matched = append(matched, v)
+			reasons = append(reasons, fmt.Sprintf("keyword:%s (%.2f)", token, v))
+		}
+	}
+	if len(matched) > 0 {
+		sort.Sort(sort.Reverse(sort.Float64Slice(matched)))
+		if len(matched) > 3 {
+			matched = matched[:3]
+		}
+		sum := 0.0
+		for _, v := range matched {
+			sum += v
+		}
+		score += dimensionWeights.Keywords * sum / 3
+	}
+
+	return score, reasons
+}
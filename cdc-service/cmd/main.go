@@ -0,0 +1,92 @@
+package main
+
+import (
+	"cdc-service/config"
+	"cdc-service/watcher"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	log.Println("Starting CDC Service...")
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to MongoDB
+	mongoClient, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatal("Failed to connect to MongoDB:", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	if err := mongoClient.Ping(context.Background(), nil); err != nil {
+		log.Fatal("MongoDB ping error:", err)
+	}
+	log.Println("Connected to MongoDB")
+
+	// Connect to NATS
+	nc, err := nats.Connect(cfg.NATSUrl, nats.Name("cdc-service"), nats.MaxReconnects(-1))
+	if err != nil {
+		log.Fatal("Failed to connect to NATS:", err)
+	}
+	defer nc.Close()
+	log.Println("Connected to NATS")
+
+	w, err := watcher.NewWatcher(cfg, mongoClient, nc)
+	if err != nil {
+		log.Fatal("Failed to create watcher:", err)
+	}
+
+	// Setup graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		log.Println("Received shutdown signal, stopping...")
+		cancel()
+	}()
+
+	// Start health check server
+	http.HandleFunc("/health", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusOK)
+		rw.Write([]byte(`{"status":"healthy","service":"cdc-service"}`))
+	})
+
+	http.HandleFunc("/status", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"service":   "cdc-service",
+			"connected": nc.IsConnected(),
+			"watches":   w.Status(),
+		})
+	})
+
+	go func() {
+		log.Println("Health check server starting on :8080")
+		if err := http.ListenAndServe(":8080", nil); err != nil {
+			log.Printf("Health check server error: %v", err)
+		}
+	}()
+
+	log.Println("CDC service is running...")
+	if err := w.Start(ctx); err != nil && err != context.Canceled {
+		log.Fatal("Watcher failed:", err)
+	}
+
+	log.Println("CDC service stopped")
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Watch describes one collection whose changes are published as events
+type Watch struct {
+	Database   string
+	Collection string
+	Kind       string // event kind used in the subject, e.g. "article"
+}
+
+// Key identifies the watch in the resume token store
+func (w Watch) Key() string {
+	return w.Database + "." + w.Collection
+}
+
+type Config struct {
+	MongoURI      string
+	NATSUrl       string
+	StateDatabase string
+	StreamName    string
+	SubjectPrefix string
+	Watches       []Watch
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	DuplicateTTL  time.Duration
+	MaxAwaitTime  time.Duration
+}
+
+// Default watch set covering every content collection
+const defaultWatches = "newsdb.articles:article,videosdb.videos:video,viraldb.stories:viral_story,memesdb.memes:meme"
+
+func Load() *Config {
+	cfg := &Config{
+		MongoURI:      getEnv("MONGO_URI", "mongodb://localhost:27017"),
+		NATSUrl:       getEnv("NATS_URL", "nats://localhost:4222"),
+		StateDatabase: getEnv("CDC_STATE_DB", "cdcdb"),
+		StreamName:    getEnv("CDC_STREAM_NAME", "CONTENT_EVENTS"),
+		SubjectPrefix: getEnv("CDC_SUBJECT_PREFIX", "content"),
+		Watches:       parseWatches(getEnv("CDC_WATCHES", defaultWatches)),
+		RetryDelay:    getDurationEnv("RETRY_DELAY", "2s"),
+		MaxRetryDelay: getDurationEnv("MAX_RETRY_DELAY", "1m"),
+		DuplicateTTL:  getDurationEnv("CDC_DUPLICATE_WINDOW", "10m"),
+		MaxAwaitTime:  getDurationEnv("CDC_MAX_AWAIT", "5s"),
+	}
+
+	if len(cfg.Watches) == 0 {
+		log.Fatal("CDC_WATCHES must list at least one db.collection:kind entry")
+	}
+
+	log.Printf("Config loaded - Stream: %s, Prefix: %s, Watches: %d",
+		cfg.StreamName, cfg.SubjectPrefix, len(cfg.Watches))
+
+	return cfg
+}
+
+// parseWatches parses "db.collection:kind,db.collection:kind"
+func parseWatches(value string) []Watch {
+	var watches []Watch
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, kind, _ := strings.Cut(entry, ":")
+		db, coll, ok := strings.Cut(target, ".")
+		if !ok || db == "" || coll == "" {
+			log.Printf("Ignoring invalid watch entry: %q", entry)
+			continue
+		}
+		if kind == "" {
+			kind = coll
+		}
+
+		watches = append(watches, Watch{Database: db, Collection: coll, Kind: kind})
+	}
+	return watches
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue string) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	duration, _ := time.ParseDuration(defaultValue)
+	return duration
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// Event types emitted for change stream operations
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// ContentEvent is published to NATS for every change in a watched collection.
+// Exactly one of the typed payloads is set for created/updated events.
+type ContentEvent struct {
+	ID            string                 `json:"id"` // Also sent as Nats-Msg-Id
+	Type          string                 `json:"type"`
+	Kind          string                 `json:"kind"`
+	Database      string                 `json:"database"`
+	Collection    string                 `json:"collection"`
+	DocumentID    string                 `json:"document_id"`
+	ClusterTime   time.Time              `json:"cluster_time"`
+	Timestamp     time.Time              `json:"timestamp"`
+	UpdatedFields map[string]interface{} `json:"updated_fields,omitempty"`
+	RemovedFields []string               `json:"removed_fields,omitempty"`
+
+	Article    *Article    `json:"article,omitempty"`
+	Video      *Video      `json:"video,omitempty"`
+	ViralStory *ViralStory `json:"viral_story,omitempty"`
+	Meme       *Meme       `json:"meme,omitempty"`
+}
+
+// Article mirrors newsdb.articles
+type Article struct {
+	Title       string `json:"title" bson:"title"`
+	Description string `json:"description" bson:"description"`
+	URL         string `json:"url" bson:"url"`
+	Image       string `json:"image" bson:"image"`
+	Source      struct {
+		Name string `json:"name" bson:"name"`
+	} `json:"source" bson:"source"`
+	PublishedAt time.Time `json:"publishedAt" bson:"publishedAt"`
+	Topic       string    `json:"topic" bson:"topic"`
+	FetchedAt   time.Time `json:"fetchedAt" bson:"fetchedAt"`
+}
+
+// Video mirrors videosdb.videos
+type Video struct {
+	VideoID      string    `json:"videoId" bson:"videoId"`
+	Title        string    `json:"title" bson:"title"`
+	Description  string    `json:"description" bson:"description"`
+	ChannelTitle string    `json:"channelTitle" bson:"channelTitle"`
+	CategoryID   string    `json:"categoryId" bson:"categoryId"`
+	CategoryName string    `json:"categoryName" bson:"categoryName"`
+	Region       string    `json:"region" bson:"region"`
+	PublishedAt  time.Time `json:"publishedAt" bson:"publishedAt"`
+	Thumbnail    string    `json:"thumbnail" bson:"thumbnail"`
+	VideoURL     string    `json:"videoUrl" bson:"videoUrl"`
+	ViewCount    int64     `json:"viewCount" bson:"viewCount"`
+	LikeCount    int64     `json:"likeCount" bson:"likeCount"`
+	Duration     string    `json:"duration" bson:"duration"`
+	FetchedAt    time.Time `json:"fetchedAt" bson:"fetchedAt"`
+}
+
+// ViralStory mirrors viraldb.stories
+type ViralStory struct {
+	Title       string    `json:"title" bson:"title"`
+	Description string    `json:"description" bson:"description"`
+	URL         string    `json:"url" bson:"url"`
+	ImageURL    string    `json:"image_url" bson:"image_url"`
+	Source      string    `json:"source" bson:"source"`
+	SourceID    string    `json:"source_id" bson:"source_id"`
+	Author      string    `json:"author" bson:"author"`
+	Category    string    `json:"category" bson:"category"`
+	PublishedAt time.Time `json:"published_at" bson:"published_at"`
+	FetchedAt   time.Time `json:"fetched_at" bson:"fetched_at"`
+	ViralScore  int       `json:"viral_score" bson:"viral_score"`
+	Upvotes     int       `json:"upvotes" bson:"upvotes"`
+	Comments    int       `json:"comments" bson:"comments"`
+}
+
+// Meme mirrors memesdb.memes
+type Meme struct {
+	PostID    string    `json:"post_id,omitempty" bson:"post_id,omitempty"`
+	Title     string    `json:"title" bson:"title"`
+	ImageURL  string    `json:"image_url" bson:"image_url"`
+	Source    string    `json:"source" bson:"source"`
+	Subreddit string    `json:"subreddit,omitempty" bson:"subreddit,omitempty"`
+	Permalink string    `json:"permalink" bson:"permalink"`
+	Author    string    `json:"author,omitempty" bson:"author,omitempty"`
+	Score     int       `json:"score" bson:"score"`
+	NSFW      bool      `json:"nsfw" bson:"nsfw"`
+	CreatedAt time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	FetchedAt time.Time `json:"fetched_at" bson:"fetched_at"`
+}
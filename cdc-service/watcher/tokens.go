@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TokenStore persists change stream resume tokens so a restart continues
+// from the last published change instead of "now"
+type TokenStore struct {
+	collection *mongo.Collection
+}
+
+type tokenDocument struct {
+	Key         string    `bson:"_id"`
+	Token       bson.Raw  `bson:"token"`
+	Invalidated bool      `bson:"invalidated"`
+	UpdatedAt   time.Time `bson:"updatedAt"`
+}
+
+func NewTokenStore(db *mongo.Database) *TokenStore {
+	return &TokenStore{collection: db.Collection("resume_tokens")}
+}
+
+// Load returns the stored token for key, or nil if none has been saved.
+// invalidated reports whether the token belongs to an invalidate event, which
+// can only be resumed with startAfter.
+func (s *TokenStore) Load(ctx context.Context, key string) (token bson.Raw, invalidated bool, err error) {
+	var doc tokenDocument
+	err = s.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return doc.Token, doc.Invalidated, nil
+}
+
+// Save stores the resume token for key
+func (s *TokenStore) Save(ctx context.Context, key string, token bson.Raw, invalidated bool) error {
+	_, err := s.collection.ReplaceOne(ctx,
+		bson.M{"_id": key},
+		tokenDocument{Key: key, Token: token, Invalidated: invalidated, UpdatedAt: time.Now()},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// Clear removes the token for key so the next watch starts from the current time
+func (s *TokenStore) Clear(ctx context.Context, key string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
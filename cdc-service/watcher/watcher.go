@@ -0,0 +1,364 @@
+package watcher
+
+import (
+	"cdc-service/config"
+	"cdc-service/model"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB error codes for resume tokens that can no longer be used
+const (
+	codeChangeStreamFatal       = 280
+	codeChangeStreamHistoryLost = 286
+)
+
+// Watcher tails change streams on the configured collections and publishes
+// each change to JetStream
+type Watcher struct {
+	config *config.Config
+	client *mongo.Client
+	js     nats.JetStreamContext
+	tokens *TokenStore
+
+	mu     sync.RWMutex
+	status map[string]*WatchStatus
+}
+
+// WatchStatus reports progress of a single collection watch
+type WatchStatus struct {
+	Collection string    `json:"collection"`
+	Kind       string    `json:"kind"`
+	Running    bool      `json:"running"`
+	Published  int64     `json:"published"`
+	Skipped    int64     `json:"skipped"`
+	LastEvent  time.Time `json:"last_event,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// changeEvent is the subset of a change stream document we use
+type changeEvent struct {
+	ID            bson.Raw            `bson:"_id"`
+	OperationType string              `bson:"operationType"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+	DocumentKey   struct {
+		ID interface{} `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument      bson.Raw `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M   `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+func NewWatcher(cfg *config.Config, client *mongo.Client, nc *nats.Conn) (*Watcher, error) {
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		config: cfg,
+		client: client,
+		js:     js,
+		tokens: NewTokenStore(client.Database(cfg.StateDatabase)),
+		status: make(map[string]*WatchStatus),
+	}
+
+	if err := w.setupStream(); err != nil {
+		return nil, err
+	}
+
+	for _, watch := range cfg.Watches {
+		w.status[watch.Key()] = &WatchStatus{Collection: watch.Key(), Kind: watch.Kind}
+	}
+
+	return w, nil
+}
+
+func (w *Watcher) setupStream() error {
+	_, err := w.js.AddStream(&nats.StreamConfig{
+		Name:       w.config.StreamName,
+		Subjects:   []string{w.config.SubjectPrefix + ".>"},
+		Retention:  nats.LimitsPolicy,
+		MaxAge:     7 * 24 * time.Hour,
+		Storage:    nats.FileStorage,
+		Duplicates: w.config.DuplicateTTL,
+	})
+	if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		return fmt.Errorf("failed to create stream %s: %w", w.config.StreamName, err)
+	}
+
+	log.Printf("JetStream stream %s ready for subjects %s.>", w.config.StreamName, w.config.SubjectPrefix)
+	return nil
+}
+
+// Start launches one goroutine per watched collection and blocks until ctx is done
+func (w *Watcher) Start(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, watch := range w.config.Watches {
+		wg.Add(1)
+		go func(watch config.Watch) {
+			defer wg.Done()
+			w.run(ctx, watch)
+		}(watch)
+	}
+
+	log.Printf("Watching %d collections", len(w.config.Watches))
+	wg.Wait()
+	return ctx.Err()
+}
+
+// run keeps a watch alive, reopening the change stream with backoff on errors
+func (w *Watcher) run(ctx context.Context, watch config.Watch) {
+	delay := w.config.RetryDelay
+
+	for {
+		err := w.watchOnce(ctx, watch)
+		if ctx.Err() != nil {
+			w.setRunning(watch, false, nil)
+			return
+		}
+
+		w.setRunning(watch, false, err)
+		log.Printf("Change stream for %s stopped: %v (retrying in %v)", watch.Key(), err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > w.config.MaxRetryDelay {
+			delay = w.config.MaxRetryDelay
+		}
+	}
+}
+
+func (w *Watcher) watchOnce(ctx context.Context, watch config.Watch) error {
+	token, invalidated, err := w.tokens.Load(ctx, watch.Key())
+	if err != nil {
+		return fmt.Errorf("failed to load resume token: %w", err)
+	}
+
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetMaxAwaitTime(w.config.MaxAwaitTime)
+	if token != nil {
+		if invalidated {
+			opts.SetStartAfter(token)
+		} else {
+			opts.SetResumeAfter(token)
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		// invalidate has to pass too, so a drop or rename saves a startAfter token
+		{{Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete", "invalidate"}},
+		}}},
+	}
+
+	collection := w.client.Database(watch.Database).Collection(watch.Collection)
+	stream, err := collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		if token != nil && isUnresumable(err) {
+			// The oplog no longer covers our position; nothing we can do but start over
+			log.Printf("WARNING: resume token for %s is no longer valid, restarting from now: %v", watch.Key(), err)
+			if clearErr := w.tokens.Clear(ctx, watch.Key()); clearErr != nil {
+				log.Printf("Failed to clear resume token for %s: %v", watch.Key(), clearErr)
+			}
+		}
+		return fmt.Errorf("failed to open change stream: %w", err)
+	}
+	defer stream.Close(context.Background())
+
+	if token != nil {
+		log.Printf("Resumed change stream for %s", watch.Key())
+	} else {
+		log.Printf("Started change stream for %s from current time", watch.Key())
+	}
+	w.setRunning(watch, true, nil)
+
+	for stream.Next(ctx) {
+		var change changeEvent
+		if err := stream.Decode(&change); err != nil {
+			return fmt.Errorf("failed to decode change event: %w", err)
+		}
+
+		if change.OperationType == "invalidate" {
+			// Collection dropped or renamed; the next watch must use startAfter
+			log.Printf("Change stream for %s invalidated", watch.Key())
+			return w.tokens.Save(ctx, watch.Key(), stream.ResumeToken(), true)
+		}
+
+		event, err := w.toContentEvent(watch, change)
+		if err != nil {
+			// Retrying cannot fix a document that does not decode, so move the
+			// token past it instead of stalling the stream on the same change
+			log.Printf("Skipping change %s on %s: %v", eventID(change.ID), watch.Key(), err)
+			if err := w.tokens.Save(ctx, watch.Key(), stream.ResumeToken(), false); err != nil {
+				return fmt.Errorf("failed to save resume token: %w", err)
+			}
+			w.recordSkipped(watch, err)
+			continue
+		}
+
+		// Publish before saving the token: a crash in between re-publishes the
+		// change on restart and JetStream drops it via Nats-Msg-Id
+		if err := w.publish(event); err != nil {
+			return err
+		}
+		if err := w.tokens.Save(ctx, watch.Key(), stream.ResumeToken(), false); err != nil {
+			return fmt.Errorf("failed to save resume token: %w", err)
+		}
+
+		w.recordPublished(watch, event)
+	}
+
+	return stream.Err()
+}
+
+// toContentEvent maps a raw change to a typed event
+func (w *Watcher) toContentEvent(watch config.Watch, change changeEvent) (*model.ContentEvent, error) {
+	event := &model.ContentEvent{
+		ID:          eventID(change.ID),
+		Kind:        watch.Kind,
+		Database:    watch.Database,
+		Collection:  watch.Collection,
+		DocumentID:  documentID(change.DocumentKey.ID),
+		ClusterTime: time.Unix(int64(change.ClusterTime.T), 0).UTC(),
+		Timestamp:   time.Now(),
+	}
+
+	switch change.OperationType {
+	case "insert":
+		event.Type = model.EventCreated
+	case "update", "replace":
+		event.Type = model.EventUpdated
+		event.UpdatedFields = change.UpdateDescription.UpdatedFields
+		event.RemovedFields = change.UpdateDescription.RemovedFields
+	case "delete":
+		event.Type = model.EventDeleted
+		return event, nil
+	default:
+		return nil, fmt.Errorf("unexpected operation type %q", change.OperationType)
+	}
+
+	// fullDocument is empty when the document was deleted before the lookup ran
+	if len(change.FullDocument) == 0 {
+		return event, nil
+	}
+
+	var err error
+	switch watch.Kind {
+	case "article":
+		event.Article = &model.Article{}
+		err = bson.Unmarshal(change.FullDocument, event.Article)
+	case "video":
+		event.Video = &model.Video{}
+		err = bson.Unmarshal(change.FullDocument, event.Video)
+	case "viral_story":
+		event.ViralStory = &model.ViralStory{}
+		err = bson.Unmarshal(change.FullDocument, event.ViralStory)
+	case "meme":
+		event.Meme = &model.Meme{}
+		err = bson.Unmarshal(change.FullDocument, event.Meme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s document %s: %w", watch.Kind, event.DocumentID, err)
+	}
+
+	return event, nil
+}
+
+func (w *Watcher) publish(event *model.ContentEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	subject := fmt.Sprintf("%s.%s.%s", w.config.SubjectPrefix, event.Kind, event.Type)
+	if _, err := w.js.Publish(subject, data, nats.MsgId(event.ID)); err != nil {
+		return fmt.Errorf("failed to publish to subject %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Status returns a snapshot of every watch
+func (w *Watcher) Status() []WatchStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var statuses []WatchStatus
+	for _, watch := range w.config.Watches {
+		statuses = append(statuses, *w.status[watch.Key()])
+	}
+	return statuses
+}
+
+func (w *Watcher) setRunning(watch config.Watch, running bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status[watch.Key()]
+	status.Running = running
+	if err != nil {
+		status.LastError = err.Error()
+	}
+}
+
+func (w *Watcher) recordPublished(watch config.Watch, event *model.ContentEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status[watch.Key()]
+	status.Published++
+	status.LastEvent = event.Timestamp
+	status.LastError = ""
+}
+
+func (w *Watcher) recordSkipped(watch config.Watch, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status[watch.Key()]
+	status.Skipped++
+	status.LastError = err.Error()
+}
+
+// eventID derives a stable ID from the resume token so a re-delivered change
+// gets the same Nats-Msg-Id as the original
+func eventID(token bson.Raw) string {
+	sum := sha1.Sum(token)
+	return hex.EncodeToString(sum[:])
+}
+
+func documentID(id interface{}) string {
+	if oid, ok := id.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return fmt.Sprint(id)
+}
+
+func isUnresumable(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == codeChangeStreamHistoryLost || cmdErr.Code == codeChangeStreamFatal
+	}
+	return false
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultPageSize = 30
+	maxPageSize     = 100
+)
+
+// sortFields maps the sort parameter to the field memes are ordered by
+var sortFields = map[string]string{
+	"hot":    "hot_rank",
+	"score":  "score",
+	"recent": "created_at",
+}
+
+func ensureIndexes(ctx context.Context) {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "image_url", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "source", Value: 1}, {Key: "hot_rank", Value: -1}, {Key: "_id", Value: -1}}},
+	}
+	for _, field := range sortFields {
+		indexes = append(indexes, mongo.IndexModel{Keys: bson.D{{Key: field, Value: -1}, {Key: "_id", Value: -1}}})
+	}
+	if _, err := memesCollection.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("Warning: Failed to create meme indexes: %v", err)
+	}
+}
+
+// pageCursor is the position after the last meme of a page: its sort value
+// and ID, so pages stay stable while new memes arrive
+type pageCursor struct {
+	Sort  string    `json:"s"`
+	Num   float64   `json:"n,omitempty"`
+	Time  time.Time `json:"t,omitempty"`
+	After string    `json:"id"`
+}
+
+func encodeCursor(sortBy string, m Meme) string {
+	cur := pageCursor{Sort: sortBy, After: m.ID}
+	switch sortBy {
+	case "score":
+		cur.Num = float64(m.Score)
+	case "recent":
+		cur.Time = m.CreatedAt
+	default:
+		cur.Num = m.HotRank
+	}
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value, sortBy string) (bson.M, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var cur pageCursor
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, err
+	}
+	if cur.Sort != sortBy {
+		return nil, fmt.Errorf("cursor was issued for sort=%s", cur.Sort)
+	}
+	id, err := primitive.ObjectIDFromHex(cur.After)
+	if err != nil {
+		return nil, err
+	}
+
+	var bound interface{} = cur.Num
+	if sortBy == "recent" {
+		bound = cur.Time
+	}
+	field := sortFields[sortBy]
+	return bson.M{"$or": []bson.M{
+		{field: bson.M{"$lt": bound}},
+		{field: bound, "_id": bson.M{"$lt": id}},
+	}}, nil
+}
+
+// responseCache holds rendered responses until the next refresh changes
+// the collection
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	body    []byte
+	etag    string
+	headers map[string]string
+}
+
+const maxCachedResponses = 500
+
+var memeResponses = &responseCache{entries: map[string]cachedResponse{}}
+
+func (r *responseCache) get(key string) (cachedResponse, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resp, ok := r.entries[key]
+	return resp, ok
+}
+
+func (r *responseCache) put(key string, resp cachedResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) >= maxCachedResponses {
+		r.entries = map[string]cachedResponse{}
+	}
+	r.entries[key] = resp
+}
+
+func (r *responseCache) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = map[string]cachedResponse{}
+}
+
+// serveCached writes a cached response, honouring If-None-Match
+func serveCached(c *gin.Context, resp cachedResponse) {
+	for k, v := range resp.headers {
+		c.Header(k, v)
+	}
+	c.Header("ETag", resp.etag)
+	c.Header("Cache-Control", "public, max-age=60")
+	if match := c.GetHeader("If-None-Match"); match != "" && strings.Contains(match, resp.etag) {
+		c.Status(304)
+		return
+	}
+	c.Data(200, "application/json; charset=utf-8", resp.body)
+}
+
+func render(value interface{}, headers map[string]string) (cachedResponse, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+	sum := sha1.Sum(body)
+	return cachedResponse{body: body, etag: `W/"` + hex.EncodeToString(sum[:10]) + `"`, headers: headers}, nil
+}
+
+// getTrendingMemes returns a page of memes as a JSON array. The cursor for
+// the next page is in the X-Next-Cursor header so existing clients that
+// expect a bare array keep working.
+//
+//	sort=hot|score|recent  source=imgflip,reddit  nsfw=exclude|include|only
+//	limit=1..100           cursor=<X-Next-Cursor of the previous page>
+func getTrendingMemes(c *gin.Context) {
+	if memesCollection == nil {
+		log.Println("memesCollection is nil in handler")
+		c.JSON(500, gin.H{"error": "db not ready"})
+		return
+	}
+
+	cacheKey := c.Request.URL.Path + "?" + c.Request.URL.RawQuery
+	if resp, ok := memeResponses.get(cacheKey); ok {
+		serveCached(c, resp)
+		return
+	}
+
+	sortBy := c.DefaultQuery("sort", "hot")
+	field, ok := sortFields[sortBy]
+	if !ok {
+		c.JSON(400, gin.H{"error": "sort must be hot, score or recent"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		limit = defaultPageSize
+	}
+
+	filter := visibleFilter()
+	if sources := c.Query("source"); sources != "" {
+		filter["source"] = bson.M{"$in": strings.Split(sources, ",")}
+	}
+	switch c.DefaultQuery("nsfw", "exclude") {
+	case "exclude":
+		filter["nsfw"] = bson.M{"$ne": true}
+	case "only":
+		filter["nsfw"] = true
+	case "include":
+	default:
+		c.JSON(400, gin.H{"error": "nsfw must be exclude, include or only"})
+		return
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := decodeCursor(cursor, sortBy)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "invalid cursor"})
+			return
+		}
+		filter = bson.M{"$and": []bson.M{filter, after}}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: field, Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit + 1)) // One extra tells us whether there's a next page
+	cursor, err := memesCollection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("DB Find error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	memes := []Meme{}
+	if err := cursor.All(ctx, &memes); err != nil {
+		log.Printf("DB cursor.All error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+
+	headers := map[string]string{}
+	if len(memes) > limit {
+		memes = memes[:limit]
+		headers["X-Next-Cursor"] = encodeCursor(sortBy, memes[len(memes)-1])
+	}
+
+	resp, err := render(memes, headers)
+	if err != nil {
+		log.Printf("Response encode error: %v", err)
+		c.JSON(500, gin.H{"error": "encode error"})
+		return
+	}
+	memeResponses.put(cacheKey, resp)
+
+	log.Printf("Serving %d memes (sort=%s)", len(memes), sortBy)
+	serveCached(c, resp)
+}
+
+// getMeme returns a single meme by ID
+func getMeme(c *gin.Context) {
+	if memesCollection == nil {
+		c.JSON(500, gin.H{"error": "db not ready"})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid meme id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	filter := visibleFilter()
+	filter["_id"] = id
+	var meme Meme
+	if err := memesCollection.FindOne(ctx, filter).Decode(&meme); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(404, gin.H{"error": "meme not found"})
+			return
+		}
+		log.Printf("DB FindOne error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+
+	resp, err := render(meme, nil)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "encode error"})
+		return
+	}
+	serveCached(c, resp)
+}
+
+// getSimilarMemes returns memes whose perceptual hash is close to the given meme's
+func getSimilarMemes(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	if memesCollection == nil {
+		c.JSON(500, gin.H{"error": "db not ready"})
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid meme id"})
+		return
+	}
+	maxDistance, err := strconv.Atoi(c.DefaultQuery("maxDistance", strconv.Itoa(defaultSimilarDistance)))
+	if err != nil || maxDistance < 0 || maxDistance > 32 {
+		maxDistance = defaultSimilarDistance
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	filter := visibleFilter()
+	filter["_id"] = id
+	var target Meme
+	if err := memesCollection.FindOne(ctx, filter).Decode(&target); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(404, gin.H{"error": "meme not found"})
+			return
+		}
+		log.Printf("DB FindOne error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	if target.DHash == "" {
+		c.JSON(200, gin.H{"meme": target, "similar": []Meme{}, "hashed": false})
+		return
+	}
+
+	// The collection holds a few hundred memes; a scan is cheaper than an index
+	filter = visibleFilter()
+	filter["_id"] = bson.M{"$ne": id}
+	filter["dhash"] = bson.M{"$exists": true}
+	cursor, err := memesCollection.Find(ctx, filter)
+	if err != nil {
+		log.Printf("DB Find error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	var candidates []Meme
+	if err := cursor.All(ctx, &candidates); err != nil {
+		log.Printf("DB cursor.All error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+
+	type similarMeme struct {
+		Meme
+		Distance int `json:"distance"`
+	}
+	similar := []similarMeme{}
+	for _, m := range candidates {
+		if d := hammingDistance(target.DHash, m.DHash); d >= 0 && d <= maxDistance {
+			similar = append(similar, similarMeme{Meme: m, Distance: d})
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].Distance < similar[j].Distance })
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	c.JSON(200, gin.H{"meme": target, "similar": similar, "hashed": true})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"strings"
+)
+
+// loadSources builds the enabled meme sources from the environment:
+//
+//	MEME_SOURCE_IMGFLIP_ENABLED  default true
+//	MEME_SOURCE_REDDIT_ENABLED   default true
+//	MEME_REDDIT_SUBREDDITS       comma-separated, default memes,dankmemes,wholesomememes
+//	MEME_REDDIT_LIMIT            posts per subreddit, default 25
+//	MEME_REDDIT_PERIOD           top.json time window, default day
+func loadSources() []MemeSource {
+	sources := []MemeSource{}
+	if getenvBool("MEME_SOURCE_IMGFLIP_ENABLED", true) {
+		sources = append(sources, &ImgflipSource{})
+	}
+	if getenvBool("MEME_SOURCE_REDDIT_ENABLED", true) {
+		subreddits := []string{}
+		for _, s := range strings.Split(getenv("MEME_REDDIT_SUBREDDITS", "memes,dankmemes,wholesomememes"), ",") {
+			s = strings.TrimPrefix(strings.TrimSpace(s), "r/")
+			if s != "" {
+				subreddits = append(subreddits, s)
+			}
+		}
+		if len(subreddits) > 0 {
+			sources = append(sources, &RedditSource{
+				Subreddits: subreddits,
+				Limit:      getenvInt("MEME_REDDIT_LIMIT", 25),
+				Period:     getenv("MEME_REDDIT_PERIOD", "day"),
+			})
+		}
+	}
+
+	names := []string{}
+	for _, s := range sources {
+		names = append(names, s.Name())
+	}
+	log.Printf("Meme sources enabled: %s", strings.Join(names, ", "))
+	return sources
+}
+
+func getenvBool(key string, fallback bool) bool {
+	switch strings.ToLower(getenv(key, "")) {
+	case "true", "1", "yes":
+		return true
+	case "false", "0", "no":
+		return false
+	}
+	return fallback
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Reposts usually differ by re-compression, resizing or a watermark,
+	// which moves a 64-bit dHash by only a few bits
+	defaultDuplicateDistance = 10
+	defaultSimilarDistance   = 20
+	maxHashImageBytes        = 8 << 20
+	maxHashImagePixels       = 40 * 1000 * 1000
+	hashWorkers              = 4
+)
+
+var hashClient = &http.Client{Timeout: 15 * time.Second}
+
+// dHash computes a 64-bit difference hash: the image is reduced to 9x8
+// grey levels and each bit records whether a pixel is brighter than its
+// right-hand neighbour. It survives scaling and re-encoding, unlike URLs.
+func dHash(img image.Image) uint64 {
+	const w, h = 9, 8
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	var grey [h][w]float64
+	for y := 0; y < h; y++ {
+		y0, y1 := blockBounds(bounds.Min.Y, srcH, y, h)
+		for x := 0; x < w; x++ {
+			x0, x1 := blockBounds(bounds.Min.X, srcW, x, w)
+
+			// Average the block so the hash reflects the region, not one
+			// pixel; large blocks are sampled on a grid
+			stepY, stepX := max(1, (y1-y0)/16), max(1, (x1-x0)/16)
+			var sum float64
+			var n int
+			for sy := y0; sy < y1; sy += stepY {
+				for sx := x0; sx < x1; sx += stepX {
+					r, g, b, _ := img.At(sx, sy).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					n++
+				}
+			}
+			grey[y][x] = sum / float64(n)
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+			if grey[y][x] > grey[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// blockBounds splits size pixels starting at min into cells and returns the
+// half-open range of cell i, never empty
+func blockBounds(min, size, i, cells int) (int, int) {
+	start := min + i*size/cells
+	end := min + (i+1)*size/cells
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+func formatHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+func parseHash(value string) (uint64, bool) {
+	hash, err := strconv.ParseUint(value, 16, 64)
+	return hash, err == nil && len(value) == 16
+}
+
+// hammingDistance counts differing bits between two stored hashes; -1 if
+// either is missing
+func hammingDistance(a, b string) int {
+	ha, okA := parseHash(a)
+	hb, okB := parseHash(b)
+	if !okA || !okB {
+		return -1
+	}
+	return bits.OnesCount64(ha ^ hb)
+}
+
+// hashImage downloads and hashes one image
+func hashImage(ctx context.Context, imageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; ScrollfeedBot/1.0)")
+
+	resp, err := hashClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image fetch returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHashImageBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("image read failed: %w", err)
+	}
+	if len(body) > maxHashImageBytes {
+		return "", fmt.Errorf("image larger than %d bytes", maxHashImageBytes)
+	}
+
+	// Check dimensions before decoding so a tiny file can't expand into gigabytes
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("image decode failed: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxHashImagePixels {
+		return "", fmt.Errorf("image dimensions %dx%d out of range", cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("image decode failed: %w", err)
+	}
+	return formatHash(dHash(img)), nil
+}
+
+// hashMemes fills in DHash for memes that don't have one, reusing hashes
+// already stored for the same image URL
+func hashMemes(ctx context.Context, memes []Meme, known map[string]string) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var failed int
+	var mu sync.Mutex
+
+	for w := 0; w < hashWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				hash, err := hashImage(ctx, memes[i].ImageURL)
+				if err != nil {
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					continue
+				}
+				memes[i].DHash = hash
+			}
+		}()
+	}
+
+	hashed := 0
+	for i := range memes {
+		if hash, ok := known[memes[i].ImageURL]; ok {
+			memes[i].DHash = hash
+			continue
+		}
+		hashed++
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	log.Printf("Hashed %d new meme images (%d failed, %d reused)", hashed, failed, len(memes)-hashed)
+}
+
+// suppressNearDuplicates keeps the first meme of each group whose hashes are
+// within maxDistance bits. Memes without a hash are kept; URL dedup still
+// applies to them.
+func suppressNearDuplicates(memes []Meme, maxDistance int) []Meme {
+	result := []Meme{}
+	suppressed := 0
+	for _, m := range memes {
+		duplicate := false
+		if m.DHash != "" {
+			for _, kept := range result {
+				if d := hammingDistance(m.DHash, kept.DHash); d >= 0 && d <= maxDistance {
+					duplicate = true
+					break
+				}
+			}
+		}
+		if duplicate {
+			suppressed++
+			continue
+		}
+		result = append(result, m)
+	}
+	if suppressed > 0 {
+		log.Printf("Suppressed %d near-duplicate memes", suppressed)
+	}
+	return result
+}
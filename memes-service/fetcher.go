@@ -1,26 +1,52 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"html"
 	"io"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 )
 
+// MemeSource is a provider of memes. Sources are queried in order on every
+// refresh and earlier sources win when duplicates are found.
+type MemeSource interface {
+	Name() string
+	Fetch(ctx context.Context) ([]Meme, error)
+}
+
+var sourceClient = &http.Client{Timeout: 20 * time.Second}
+
 type ImgflipResponse struct {
 	Success bool `json:"success"`
 	Data    struct {
 		Memes []struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-			URL  string `json:"url"`
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			URL      string `json:"url"`
+			Width    int    `json:"width"`
+			Height   int    `json:"height"`
+			Captions int    `json:"captions"`
 		} `json:"memes"`
 	} `json:"data"`
 }
 
-func FetchImgflipMemes() ([]Meme, error) {
+// ImgflipSource returns Imgflip's popular meme templates
+type ImgflipSource struct{}
+
+func (s *ImgflipSource) Name() string { return "imgflip" }
+
+func (s *ImgflipSource) Fetch(ctx context.Context) ([]Meme, error) {
 	log.Println("Fetching memes from Imgflip...")
-	resp, err := http.Get("https://api.imgflip.com/get_memes")
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.imgflip.com/get_memes", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sourceClient.Do(req)
 	if err != nil {
 		log.Printf("Imgflip fetch error: %v", err)
 		return nil, err
@@ -32,78 +58,143 @@ func FetchImgflipMemes() ([]Meme, error) {
 		log.Printf("Imgflip unmarshal error: %v", err)
 		return nil, err
 	}
+	now := time.Now()
 	memes := []Meme{}
 	for _, m := range result.Data.Memes {
 		memes = append(memes, Meme{
+			PostID:    m.ID,
 			Title:     m.Name,
 			ImageURL:  m.URL,
-			Source:    "imgflip",
+			Source:    s.Name(),
 			Permalink: m.URL,
+			Score:     m.Captions, // Times the template has been captioned
+			Width:     m.Width,
+			Height:    m.Height,
+			FetchedAt: now,
 		})
 	}
 	log.Printf("Fetched %d memes from Imgflip", len(memes))
 	return memes, nil
 }
 
-// Reddit fetcher (top memes from r/memes)
+// Reddit fetcher (top posts from a list of subreddits)
 type RedditListing struct {
 	Data struct {
 		Children []struct {
-			Data struct {
-				Title     string `json:"title"`
-				URL       string `json:"url"`
-				Permalink string `json:"permalink"`
-			} `json:"data"`
+			Data RedditPost `json:"data"`
 		} `json:"children"`
 	} `json:"data"`
 }
 
-func FetchRedditMemes() ([]Meme, error) {
-	log.Println("Fetching memes from Reddit...")
-	url := "https://www.reddit.com/r/memes/top.json?limit=20&t=day"
-	req, _ := http.NewRequest("GET", url, nil)
+type RedditPost struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	URL        string  `json:"url"`
+	Permalink  string  `json:"permalink"`
+	Author     string  `json:"author"`
+	Subreddit  string  `json:"subreddit"`
+	Score      int     `json:"score"`
+	CreatedUTC float64 `json:"created_utc"`
+	Over18     bool    `json:"over_18"`
+	Spoiler    bool    `json:"spoiler"`
+	Preview    struct {
+		Images []struct {
+			Source struct {
+				URL    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"source"`
+		} `json:"images"`
+	} `json:"preview"`
+}
+
+// RedditSource returns top image posts from the configured subreddits
+type RedditSource struct {
+	Subreddits []string
+	Limit      int
+	Period     string // hour, day, week, month, year or all
+}
+
+func (s *RedditSource) Name() string { return "reddit" }
+
+func (s *RedditSource) Fetch(ctx context.Context) ([]Meme, error) {
+	memes := []Meme{}
+	var lastErr error
+	for _, subreddit := range s.Subreddits {
+		fetched, err := s.fetchSubreddit(ctx, subreddit)
+		if err != nil {
+			log.Printf("Reddit fetch error for r/%s: %v", subreddit, err)
+			lastErr = err
+			continue
+		}
+		memes = append(memes, fetched...)
+	}
+	// Only an error when every subreddit failed
+	if len(memes) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	log.Printf("Fetched %d memes from Reddit across %d subreddits", len(memes), len(s.Subreddits))
+	return memes, nil
+}
+
+func (s *RedditSource) fetchSubreddit(ctx context.Context, subreddit string) ([]Meme, error) {
+	log.Printf("Fetching memes from r/%s...", subreddit)
+	url := fmt.Sprintf("https://www.reddit.com/r/%s/top.json?limit=%d&t=%s", subreddit, s.Limit, s.Period)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; ScrollfeedBot/1.0)")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := sourceClient.Do(req)
 	if err != nil {
-		log.Printf("Reddit fetch error: %v", err)
 		return nil, err
 	}
 	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	
-	// Debug: Log response status and first 200 chars of body
-	log.Printf("Reddit response status: %d", resp.StatusCode)
-	if len(body) > 200 {
-		log.Printf("Reddit response body preview: %s...", string(body[:200]))
-	} else {
-		log.Printf("Reddit response body: %s", string(body))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reddit returned %s", resp.Status)
 	}
-	
+	body, _ := io.ReadAll(resp.Body)
+
 	var listing RedditListing
 	if err := json.Unmarshal(body, &listing); err != nil {
-		log.Printf("Reddit unmarshal error: %v", err)
-		log.Printf("Response body was: %s", string(body))
-		return nil, err
+		preview := body
+		if len(preview) > 200 {
+			preview = preview[:200]
+		}
+		return nil, fmt.Errorf("unmarshal error: %w (body: %s)", err, preview)
 	}
+
+	now := time.Now()
 	memes := []Meme{}
 	for _, child := range listing.Data.Children {
-		m := child.Data
-		if m.URL != "" && (endsWith(m.URL, ".jpg") || endsWith(m.URL, ".png")) {
-			memes = append(memes, Meme{
-				Title:     m.Title,
-				ImageURL:  m.URL,
-				Source:    "reddit",
-				Permalink: "https://reddit.com" + m.Permalink,
-			})
+		p := child.Data
+		if !isImageURL(p.URL) {
+			continue
+		}
+		meme := Meme{
+			PostID:    p.ID,
+			Title:     html.UnescapeString(p.Title),
+			ImageURL:  p.URL,
+			Source:    s.Name(),
+			Subreddit: p.Subreddit,
+			Permalink: "https://reddit.com" + p.Permalink,
+			Author:    p.Author,
+			Score:     p.Score,
+			NSFW:      p.Over18,
+			Spoiler:   p.Spoiler,
+			CreatedAt: time.Unix(int64(p.CreatedUTC), 0).UTC(),
+			FetchedAt: now,
 		}
+		if len(p.Preview.Images) > 0 {
+			meme.Width = p.Preview.Images[0].Source.Width
+			meme.Height = p.Preview.Images[0].Source.Height
+		}
+		memes = append(memes, meme)
 	}
-	log.Printf("Fetched %d memes from Reddit", len(memes))
 	return memes, nil
 }
 
-func endsWith(s, suffix string) bool {
-	if len(s) < len(suffix) {
-		return false
-	}
-	return s[len(s)-len(suffix):] == suffix
+func isImageURL(url string) bool {
+	url = strings.ToLower(url)
+	return strings.HasSuffix(url, ".jpg") || strings.HasSuffix(url, ".jpeg") || strings.HasSuffix(url, ".png")
 }
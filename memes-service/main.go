@@ -4,15 +4,18 @@ import (
 	"context"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var mongoClient *mongo.Client
 var memesCollection *mongo.Collection
+var memeSources []MemeSource
 
 func main() {
 	ctx := context.Background()
@@ -28,7 +31,11 @@ func main() {
 	log.Println("MongoDB connection successful")
 	mongoClient = client
 	memesCollection = client.Database("memesdb").Collection("memes")
+	takedownsCollection = client.Database("memesdb").Collection("takedowns")
+	blocklist = loadBlocklist()
 
+	ensureIndexes(ctx)
+	memeSources = loadSources()
 	go backgroundRefresh(ctx)
 
 	r := gin.Default()
@@ -40,10 +47,16 @@ func main() {
 	api := r.Group("/memes-api")
 	{
 		api.GET("/memes/trending", getTrendingMemes)
+		api.GET("/memes/:id", getMeme)
+		api.GET("/memes/:id/similar", getSimilarMemes)
+		registerAdminRoutes(api.Group("/memes/admin", adminAuth()))
 	}
 	
 	// Also keep the original route for direct access and health checks
 	r.GET("/memes/trending", getTrendingMemes)
+	r.GET("/memes/:id", getMeme)
+	r.GET("/memes/:id/similar", getSimilarMemes)
+	registerAdminRoutes(r.Group("/memes/admin", adminAuth()))
 	
 	r.Run(":8080")
 }
@@ -56,6 +69,13 @@ func getenv(key, fallback string) string {
 	return v
 }
 
+func getenvInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
 func backgroundRefresh(ctx context.Context) {
 	for {
 		log.Println("Refreshing memes in background...")
@@ -65,15 +85,16 @@ func backgroundRefresh(ctx context.Context) {
 }
 
 func refreshMemes(ctx context.Context) {
-	imgflip, err1 := FetchImgflipMemes()
-	if err1 != nil {
-		log.Printf("Error fetching Imgflip memes: %v", err1)
-	}
-	reddit, err2 := FetchRedditMemes()
-	if err2 != nil {
-		log.Printf("Error fetching Reddit memes: %v", err2)
+	fetched := []Meme{}
+	for _, source := range memeSources {
+		memes, err := source.Fetch(ctx)
+		if err != nil {
+			log.Printf("Error fetching %s memes: %v", source.Name(), err)
+			continue
+		}
+		fetched = append(fetched, memes...)
 	}
-	memes := deduplicateMemes(append(imgflip, reddit...))
+	memes := deduplicateMemes(fetched)
 	if len(memes) == 0 {
 		log.Println("No memes fetched from sources.")
 		return
@@ -82,20 +103,57 @@ func refreshMemes(ctx context.Context) {
 		log.Println("memesCollection is nil, skipping DB update")
 		return
 	}
-	_, err := memesCollection.DeleteMany(ctx, map[string]interface{}{})
-	if err != nil {
-		log.Printf("Error deleting old memes: %v", err)
-	}
-	docs := []interface{}{}
+
+	// Same meme reposted under a different URL is caught by image hash
+	hashMemes(ctx, memes, storedHashes(ctx))
+	memes = suppressNearDuplicates(memes, getenvInt("MEME_DUPLICATE_DISTANCE", defaultDuplicateDistance))
+
+	assignHotRanks(memes, time.Now())
+	moderateMemes(ctx, memes)
+
+	// Upsert by image URL so IDs stay stable across refreshes, then drop
+	// memes that are no longer in any source
+	models := []mongo.WriteModel{}
+	imageURLs := []string{}
 	for _, m := range memes {
-		docs = append(docs, m)
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"image_url": m.ImageURL}).
+			SetReplacement(m).
+			SetUpsert(true))
+		imageURLs = append(imageURLs, m.ImageURL)
 	}
-	_, err = memesCollection.InsertMany(ctx, docs)
+	_, err := memesCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
 	if err != nil {
-		log.Printf("Error inserting memes: %v", err)
+		log.Printf("Error upserting memes: %v", err)
+		return
+	}
+	deleted, err := memesCollection.DeleteMany(ctx, bson.M{"image_url": bson.M{"$nin": imageURLs}})
+	if err != nil {
+		log.Printf("Error deleting old memes: %v", err)
 	} else {
-		log.Printf("Inserted %d memes", len(memes))
+		log.Printf("Stored %d memes, removed %d stale", len(memes), deleted.DeletedCount)
 	}
+	memeResponses.invalidate()
+}
+
+// storedHashes maps image URL to the hash already computed for it
+func storedHashes(ctx context.Context) map[string]string {
+	known := map[string]string{}
+	cursor, err := memesCollection.Find(ctx, bson.M{"dhash": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"image_url": 1, "dhash": 1}))
+	if err != nil {
+		log.Printf("Error loading stored meme hashes: %v", err)
+		return known
+	}
+	var memes []Meme
+	if err := cursor.All(ctx, &memes); err != nil {
+		log.Printf("Error decoding stored meme hashes: %v", err)
+		return known
+	}
+	for _, m := range memes {
+		known[m.ImageURL] = m.DHash
+	}
+	return known
 }
 
 func deduplicateMemes(memes []Meme) []Meme {
@@ -109,26 +167,3 @@ func deduplicateMemes(memes []Meme) []Meme {
 	}
 	return result
 }
-
-func getTrendingMemes(c *gin.Context) {
-	ctx := context.Background()
-	if memesCollection == nil {
-		log.Println("memesCollection is nil in handler")
-		c.JSON(500, gin.H{"error": "db not ready"})
-		return
-	}
-	cursor, err := memesCollection.Find(ctx, map[string]interface{}{})
-	if err != nil {
-		log.Printf("DB Find error: %v", err)
-		c.JSON(500, gin.H{"error": "db error"})
-		return
-	}
-	var memes []Meme
-	if err := cursor.All(ctx, &memes); err != nil {
-		log.Printf("DB cursor.All error: %v", err)
-		c.JSON(500, gin.H{"error": "db error"})
-		return
-	}
-	log.Printf("Serving %d memes", len(memes))
-	c.JSON(200, memes)
-}
@@ -1,9 +1,25 @@
 package main
 
+import "time"
+
 type Meme struct {
-	ID        string `bson:"_id,omitempty" json:"id"`
-	Title     string `bson:"title" json:"title"`
-	ImageURL  string `bson:"image_url" json:"image_url"`
-	Source    string `bson:"source" json:"source"`
-	Permalink string `bson:"permalink" json:"permalink"`
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	PostID    string    `bson:"post_id,omitempty" json:"post_id,omitempty"` // ID at the source
+	Title     string    `bson:"title" json:"title"`
+	ImageURL  string    `bson:"image_url" json:"image_url"`
+	Source    string    `bson:"source" json:"source"`
+	Subreddit string    `bson:"subreddit,omitempty" json:"subreddit,omitempty"`
+	Permalink string    `bson:"permalink" json:"permalink"`
+	Author    string    `bson:"author,omitempty" json:"author,omitempty"`
+	Score     int       `bson:"score" json:"score"`
+	NSFW      bool      `bson:"nsfw" json:"nsfw"`
+	Spoiler   bool      `bson:"spoiler" json:"spoiler"`
+	Width     int       `bson:"width,omitempty" json:"width,omitempty"`
+	Height    int       `bson:"height,omitempty" json:"height,omitempty"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"` // Zero for Imgflip templates, which sort last
+	FetchedAt time.Time `bson:"fetched_at" json:"fetched_at"`
+	HotRank   float64   `bson:"hot_rank" json:"hot_rank"`
+	DHash     string    `bson:"dhash,omitempty" json:"dhash,omitempty"` // 64-bit perceptual hash, hex
+
+	Moderation *Moderation `bson:"moderation,omitempty" json:"moderation,omitempty"`
 }
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Moderation records why a meme is flagged or hidden. NSFW and spoiler are
+// flags clients filter on; blocklist hits and takedowns hide the meme.
+type Moderation struct {
+	Hidden    bool      `bson:"hidden" json:"hidden"`
+	Reasons   []string  `bson:"reasons,omitempty" json:"reasons,omitempty"`
+	CheckedAt time.Time `bson:"checked_at" json:"checked_at"`
+}
+
+// Takedown is a manual removal, keyed by image URL so it survives the meme
+// dropping out of the sources and coming back with a new ID
+type Takedown struct {
+	ImageURL  string    `bson:"_id" json:"image_url"`
+	MemeID    string    `bson:"meme_id,omitempty" json:"meme_id,omitempty"`
+	Reason    string    `bson:"reason" json:"reason"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Blocklist hides memes whose title contains a keyword or whose image or
+// permalink is on a blocked domain (subdomains included)
+type Blocklist struct {
+	Keywords []string `json:"keywords"`
+	Domains  []string `json:"domains"`
+
+	patterns []*regexp.Regexp
+}
+
+var takedownsCollection *mongo.Collection
+var blocklist *Blocklist
+
+// loadBlocklist reads MODERATION_BLOCKLIST_FILE (JSON with keywords and
+// domains) and adds MODERATION_BLOCKED_KEYWORDS and MODERATION_BLOCKED_DOMAINS
+func loadBlocklist() *Blocklist {
+	b := &Blocklist{}
+	if path := getenv("MODERATION_BLOCKLIST_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Warning: Failed to read blocklist %s: %v", path, err)
+		} else if err := json.Unmarshal(data, b); err != nil {
+			log.Printf("Warning: Failed to parse blocklist %s: %v", path, err)
+		}
+	}
+	b.Keywords = append(b.Keywords, splitList(getenv("MODERATION_BLOCKED_KEYWORDS", ""))...)
+	b.Domains = append(b.Domains, splitList(getenv("MODERATION_BLOCKED_DOMAINS", ""))...)
+
+	for _, keyword := range b.Keywords {
+		b.patterns = append(b.patterns, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(keyword)+`\b`))
+	}
+	log.Printf("Moderation blocklist: %d keywords, %d domains", len(b.Keywords), len(b.Domains))
+	return b
+}
+
+// check returns the blocklist reasons matching text and URLs
+func (b *Blocklist) check(text string, urls ...string) []string {
+	reasons := []string{}
+	for i, pattern := range b.patterns {
+		if pattern.MatchString(text) {
+			reasons = append(reasons, "blocked_keyword:"+b.Keywords[i])
+		}
+	}
+	for _, raw := range urls {
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		for _, domain := range b.Domains {
+			domain = strings.ToLower(domain)
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				reasons = append(reasons, "blocked_domain:"+domain)
+			}
+		}
+	}
+	return reasons
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// moderate evaluates a meme against flags, the blocklist and a takedown
+func moderate(m Meme, takedown *Takedown) *Moderation {
+	mod := &Moderation{CheckedAt: time.Now()}
+	if m.NSFW {
+		mod.Reasons = append(mod.Reasons, "nsfw")
+	}
+	if m.Spoiler {
+		mod.Reasons = append(mod.Reasons, "spoiler")
+	}
+	if blocked := blocklist.check(m.Title, m.ImageURL, m.Permalink); len(blocked) > 0 {
+		mod.Hidden = true
+		mod.Reasons = append(mod.Reasons, blocked...)
+	}
+	if takedown != nil {
+		mod.Hidden = true
+		mod.Reasons = append(mod.Reasons, "takedown:"+takedown.Reason)
+	}
+	return mod
+}
+
+// moderateMemes sets Moderation on a refresh batch
+func moderateMemes(ctx context.Context, memes []Meme) {
+	takedowns := map[string]*Takedown{}
+	cursor, err := takedownsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		log.Printf("Error loading takedowns: %v", err)
+	} else {
+		var all []Takedown
+		if err := cursor.All(ctx, &all); err != nil {
+			log.Printf("Error decoding takedowns: %v", err)
+		}
+		for i := range all {
+			takedowns[all[i].ImageURL] = &all[i]
+		}
+	}
+
+	hidden := 0
+	for i := range memes {
+		memes[i].Moderation = moderate(memes[i], takedowns[memes[i].ImageURL])
+		if memes[i].Moderation.Hidden {
+			hidden++
+			log.Printf("Hiding meme %q: %s", memes[i].Title, strings.Join(memes[i].Moderation.Reasons, ", "))
+		}
+	}
+	if hidden > 0 {
+		log.Printf("Moderation hid %d of %d memes", hidden, len(memes))
+	}
+}
+
+// visibleFilter excludes memes hidden by moderation
+func visibleFilter() bson.M {
+	return bson.M{"moderation.hidden": bson.M{"$ne": true}}
+}
+
+// adminAuth guards the admin API with a shared token from ADMIN_TOKEN; the
+// API is off when no token is configured
+func adminAuth() gin.HandlerFunc {
+	token := getenv("ADMIN_TOKEN", "")
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(503, gin.H{"error": "admin API disabled"})
+			return
+		}
+		if c.GetHeader("X-Admin-Token") != token {
+			c.AbortWithStatusJSON(401, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func registerAdminRoutes(r gin.IRoutes) {
+	r.GET("/takedowns", listTakedowns)
+	r.POST("/takedowns", createTakedown)
+	r.DELETE("/takedowns/:id", deleteTakedown)
+	r.GET("/hidden", listHiddenMemes)
+}
+
+func listTakedowns(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := takedownsCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		log.Printf("DB Find error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	takedowns := []Takedown{}
+	if err := cursor.All(ctx, &takedowns); err != nil {
+		log.Printf("DB cursor.All error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	c.JSON(200, gin.H{"takedowns": takedowns})
+}
+
+// createTakedown hides a meme by ID immediately and on every later refresh
+func createTakedown(c *gin.Context) {
+	var req struct {
+		ID     string `json:"id" binding:"required"`
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "id and reason are required"})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid meme id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var meme Meme
+	if err := memesCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&meme); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(404, gin.H{"error": "meme not found"})
+			return
+		}
+		log.Printf("DB FindOne error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+
+	takedown := Takedown{ImageURL: meme.ImageURL, MemeID: req.ID, Reason: req.Reason, CreatedAt: time.Now()}
+	if _, err := takedownsCollection.ReplaceOne(ctx, bson.M{"_id": takedown.ImageURL}, takedown, options.Replace().SetUpsert(true)); err != nil {
+		log.Printf("Error storing takedown: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	if err := applyModeration(ctx, meme, &takedown); err != nil {
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+
+	log.Printf("Meme %s taken down: %s", req.ID, req.Reason)
+	c.JSON(201, takedown)
+}
+
+// deleteTakedown lifts a takedown; the meme is re-evaluated against the
+// remaining rules rather than simply unhidden
+func deleteTakedown(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid meme id"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	// Takedowns are keyed by image URL so they outlive a purge and re-fetch
+	// of the meme, which gives it a new id; meme_id only finds the original
+	var meme Meme
+	err = memesCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&meme)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("Error loading meme %s: %v", c.Param("id"), err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	found := err == nil
+
+	filter := bson.M{"meme_id": c.Param("id")}
+	if found {
+		filter = bson.M{"$or": []bson.M{{"_id": meme.ImageURL}, filter}}
+	}
+
+	var takedown Takedown
+	if err := takedownsCollection.FindOneAndDelete(ctx, filter).Decode(&takedown); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(404, gin.H{"error": "takedown not found"})
+			return
+		}
+		log.Printf("Error deleting takedown: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+
+	if found {
+		if err := applyModeration(ctx, meme, nil); err != nil {
+			log.Printf("Error re-moderating meme %s: %v", c.Param("id"), err)
+			c.JSON(500, gin.H{"error": "db error"})
+			return
+		}
+	}
+
+	c.JSON(200, gin.H{"message": "takedown removed", "takedown": takedown})
+}
+
+func applyModeration(ctx context.Context, meme Meme, takedown *Takedown) error {
+	id, err := primitive.ObjectIDFromHex(meme.ID)
+	if err != nil {
+		return err
+	}
+	mod := moderate(meme, takedown)
+	if _, err := memesCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"moderation": mod}}); err != nil {
+		log.Printf("Error updating meme moderation: %v", err)
+		return err
+	}
+	memeResponses.invalidate()
+	return nil
+}
+
+func listHiddenMemes(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := memesCollection.Find(ctx, bson.M{"moderation.hidden": true})
+	if err != nil {
+		log.Printf("DB Find error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	memes := []Meme{}
+	if err := cursor.All(ctx, &memes); err != nil {
+		log.Printf("DB cursor.All error: %v", err)
+		c.JSON(500, gin.H{"error": "db error"})
+		return
+	}
+	c.JSON(200, gin.H{"count": len(memes), "memes": memes})
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// Imgflip templates have no post time; treat them as a day old so they
+	// sit below fresh Reddit posts of similar standing
+	templateAge = 24 * time.Hour
+	hotGravity  = 1.5
+)
+
+// assignHotRanks sets HotRank on a refresh batch. Raw scores aren't
+// comparable across sources (Imgflip caption counts run to millions), so
+// each meme's score is first turned into its percentile within its own
+// source, then decayed by age.
+func assignHotRanks(memes []Meme, now time.Time) {
+	bySource := map[string][]int{}
+	for i := range memes {
+		bySource[memes[i].Source] = append(bySource[memes[i].Source], i)
+	}
+
+	for _, indexes := range bySource {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return memes[indexes[a]].Score < memes[indexes[b]].Score
+		})
+		for rank, i := range indexes {
+			percentile := 1.0
+			if len(indexes) > 1 {
+				percentile = float64(rank) / float64(len(indexes)-1)
+			}
+
+			age := templateAge
+			if !memes[i].CreatedAt.IsZero() {
+				age = now.Sub(memes[i].CreatedAt)
+			}
+			hours := math.Max(age.Hours(), 0)
+
+			memes[i].HotRank = (0.1 + percentile) / math.Pow(hours+2, hotGravity)
+		}
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"news-fetcher-service/classify"
 	"news-fetcher-service/config"
+	"news-fetcher-service/langdetect"
 	"news-fetcher-service/model"
 	"strings"
 	"time"
@@ -58,6 +60,20 @@ func (f *Fetcher) ensureIndexes() {
 		{
 			Keys: bson.D{{Key: "publishedAt", Value: -1}},
 		},
+		{
+			Keys: bson.D{
+				{Key: "lang", Value: 1},
+				{Key: "topic", Value: 1},
+				{Key: "publishedAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "topic", Value: 1},
+				{Key: "category", Value: 1},
+				{Key: "publishedAt", Value: -1},
+			},
+		},
 	}
 
 	_, err := collection.Indexes().CreateMany(ctx, indexes)
@@ -180,6 +196,7 @@ func (f *Fetcher) fetchPage(ctx context.Context, url, region string) ([]model.Ar
 	for _, apiArticle := range apiResponse.Articles {
 		// Validate and clean up image URL
 		imageURL := f.validateImageURL(apiArticle.URLToImage)
+		category := classify.ForArticle(apiArticle.Title, apiArticle.Description)
 
 		article := model.Article{
 			Title:       apiArticle.Title,
@@ -191,9 +208,12 @@ func (f *Fetcher) fetchPage(ctx context.Context, url, region string) ([]model.Ar
 			}{
 				Name: apiArticle.Source.Name,
 			},
-			PublishedAt: apiArticle.PublishedAt,
-			Topic:       region,
-			FetchedAt:   now,
+			PublishedAt:        apiArticle.PublishedAt,
+			Topic:              region,
+			Lang:               langdetect.ForArticle(apiArticle.Title, apiArticle.Description, region),
+			Category:           category.Category,
+			CategoryConfidence: category.Confidence,
+			FetchedAt:          now,
 		}
 		articles = append(articles, article)
 	}
@@ -10,9 +10,12 @@ type Article struct {
 	Source      struct {
 		Name string `json:"name" bson:"name"`
 	} `json:"source" bson:"source"`
-	PublishedAt time.Time `json:"publishedAt" bson:"publishedAt"`
-	Topic       string    `json:"topic" bson:"topic"`
-	FetchedAt   time.Time `json:"fetchedAt" bson:"fetchedAt"`
+	PublishedAt        time.Time `json:"publishedAt" bson:"publishedAt"`
+	Topic              string    `json:"topic" bson:"topic"`
+	Lang               string    `json:"lang,omitempty" bson:"lang,omitempty"`
+	Category           string    `json:"category,omitempty" bson:"category,omitempty"`
+	CategoryConfidence float64   `json:"categoryConfidence,omitempty" bson:"categoryConfidence,omitempty"` // Classifier posterior, 0..1
+	FetchedAt          time.Time `json:"fetchedAt" bson:"fetchedAt"`
 }
 
 type FetchRequest struct {
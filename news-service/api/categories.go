@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"news-service/classify"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// categoriesHandler returns article counts per classifier category, overall
+// and per region. Articles stored before classification count as uncategorised.
+func categoriesHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	match := bson.M{}
+	region := mapRegionToCode(c.Query("region"))
+	if region != "" {
+		match["topic"] = region
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"region":   "$topic",
+				"category": bson.M{"$ifNull": []interface{}{"$category", "uncategorised"}},
+			},
+			"count":         bson.M{"$sum": 1},
+			"avgConfidence": bson.M{"$avg": "$categoryConfidence"},
+		}},
+		{"$sort": bson.M{"count": -1}},
+	}
+
+	cursor, err := dbmngo.Collection("articles").Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("Category aggregation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Categories query failed"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Region   string `bson:"region"`
+			Category string `bson:"category"`
+		} `bson:"_id"`
+		Count         int64   `bson:"count"`
+		AvgConfidence float64 `bson:"avgConfidence"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		log.Printf("Category cursor decode failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Data processing failed"})
+		return
+	}
+
+	totals := make(map[string]int64)
+	byRegion := make(map[string]map[string]int64)
+	for _, row := range rows {
+		totals[row.ID.Category] += row.Count
+		if byRegion[row.ID.Region] == nil {
+			byRegion[row.ID.Region] = make(map[string]int64)
+		}
+		byRegion[row.ID.Region][row.ID.Category] = row.Count
+	}
+
+	categories := make([]gin.H, 0, len(classify.Categories()))
+	for _, category := range classify.Categories() {
+		categories = append(categories, gin.H{"category": category, "count": totals[category]})
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"categories":    categories,
+		"byRegion":      byRegion,
+		"uncategorised": totals["uncategorised"],
+		"region":        region,
+		"timestamp":     time.Now(),
+	})
+}
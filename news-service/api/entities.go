@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"news-service/entity"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var entityService *entity.Service
+
+// entityHandler returns an entity with its recent articles and daily mention timeline
+func entityHandler(c *gin.Context) {
+	start := time.Now()
+	region := mapRegionToCode(c.Query("region"))
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "14"))
+	if days < 1 || days > 90 {
+		days = 14
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	found, err := entityService.Lookup(ctx, c.Param("name"))
+	if errors.Is(err, entity.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Entity lookup failed for %s: %v", c.Param("name"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Entity query failed"})
+		return
+	}
+
+	articles, err := entityService.RecentArticles(ctx, found.ID, region, int64(limit))
+	if err != nil {
+		log.Printf("Failed to load articles for entity %s: %v", found.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Entity query failed"})
+		return
+	}
+	proxyArticleImages(articles)
+
+	timeline, err := entityService.Timeline(ctx, found.ID, region, days)
+	if err != nil {
+		log.Printf("Failed to load timeline for entity %s: %v", found.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Entity query failed"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"entity":   found,
+		"articles": articles,
+		"timeline": timeline,
+		"metadata": gin.H{
+			"region":       region,
+			"limit":        limit,
+			"days":         days,
+			"responseTime": time.Since(start).String(),
+		},
+	})
+}
+
+// trendingEntitiesHandler ranks entities by recent mentions and growth
+func trendingEntitiesHandler(c *gin.Context) {
+	region := mapRegionToCode(c.Query("region"))
+	typ := c.Query("type")
+	if typ != "" && typ != entity.TypePerson && typ != entity.TypeOrganization && typ != entity.TypePlace {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be person, organization or place"})
+		return
+	}
+
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if hours < 1 || hours > 168 {
+		hours = 24
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	trending, err := entityService.TrendingEntities(ctx, region, typ, time.Duration(hours)*time.Hour, limit)
+	if err != nil {
+		log.Printf("Trending entities query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Trending entities query failed"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=120")
+	c.JSON(http.StatusOK, gin.H{
+		"entities":  trending,
+		"region":    region,
+		"type":      typ,
+		"hours":     hours,
+		"timestamp": time.Now(),
+	})
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"news-service/feed"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var feedService *feed.Service
+
+// feedHandler serves the mixed scroll feed across news, videos, viral stories and memes
+func feedHandler(c *gin.Context) {
+	start := time.Now()
+	config := feedService.Config()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(config.DefaultLimit)))
+	if limit < 1 || limit > config.MaxLimit {
+		limit = config.DefaultLimit
+	}
+
+	query := feed.Query{
+		Limit:          limit,
+		Sort:           c.DefaultQuery("sort", "blend"),
+		Types:          splitList(c.Query("types")),
+		Region:         mapRegionToCode(c.Query("region")),
+		Categories:     splitList(c.Query("categories")),
+		ExcludeSources: splitList(c.Query("exclude_sources")),
+	}
+
+	if !feed.IsValidSort(query.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of blend, recent, top"})
+		return
+	}
+
+	if mixParam := c.Query("mix"); mixParam != "" {
+		mix, err := feed.ParseMix(mixParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query.Mix = mix
+	}
+
+	if cursorParam := c.Query("cursor"); cursorParam != "" {
+		cursor, err := feed.DecodeCursor(cursorParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query.Cursor = cursor
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	page, err := feedService.Page(ctx, query)
+	if err != nil {
+		log.Printf("Feed query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Feed query failed"})
+		return
+	}
+
+	log.Printf("Returned %d feed items (sort=%s, region=%s) in %v",
+		len(page.Items), query.Sort, query.Region, time.Since(start))
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":      page.Items,
+		"nextCursor": page.NextCursor,
+		"metadata": gin.H{
+			"count":        len(page.Items),
+			"limit":        limit,
+			"sort":         query.Sort,
+			"region":       query.Region,
+			"hasMore":      page.HasMore,
+			"responseTime": time.Since(start).String(),
+		},
+	})
+}
+
+// splitList parses a comma-separated query value, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
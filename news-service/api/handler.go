@@ -27,6 +27,10 @@ func mapRegionToCode(region string) string {
 	}
 	
 	normalized := strings.ToLower(strings.TrimSpace(region))
+	// Locale tags like en-IN or hi_IN carry the region after the language
+	if i := strings.LastIndexAny(normalized, "-_"); i >= 0 {
+		normalized = normalized[i+1:]
+	}
 	if code, exists := regionMap[normalized]; exists {
 		return code
 	}
@@ -59,6 +63,12 @@ func enhancedNewsHandler(c *gin.Context, db *mongo.Database) {
 	if region != "" {
 		filter["topic"] = region
 	}
+	if lang := inFilter(c.Query("lang")); lang != nil {
+		filter["lang"] = lang
+	}
+	if category := inFilter(c.Query("category")); category != nil {
+		filter["category"] = category
+	}
 
 	// Add timestamp-based consistency
 	// Only show articles that were fetched before request started
@@ -97,7 +107,11 @@ func enhancedNewsHandler(c *gin.Context, db *mongo.Database) {
 						"$publishedat",
 					},
 				},
-				"topic": 1,
+				"topic":              1,
+				"lang":               1,
+				"category":           1,
+				"categoryConfidence": 1,
+				"entities":           1,
 				"fetchedAt": bson.M{
 					"$ifNull": []interface{}{
 						"$fetchedAt",
@@ -126,6 +140,10 @@ func enhancedNewsHandler(c *gin.Context, db *mongo.Database) {
 		return
 	}
 
+	// Re-rank within the page; pagination stays chronological
+	results, personalized := personalizeArticles(c, results)
+	proxyArticleImages(results)
+
 	// Get total count for pagination metadata (with same filter)
 	totalCount, _ := db.Collection("articles").CountDocuments(ctx, filter)
 	totalPages := (int(totalCount) + limit - 1) / limit
@@ -141,12 +159,20 @@ func enhancedNewsHandler(c *gin.Context, db *mongo.Database) {
 			"hasNext":      page < totalPages,
 			"hasPrev":      page > 1,
 			"region":       region,
+			"lang":         c.Query("lang"),
+			"category":     c.Query("category"),
+			"personalized": personalized,
 			"responseTime": time.Since(start).String(),
 		},
 	}
 
 	// Add cache headers for better client-side caching
-	c.Header("Cache-Control", "public, max-age=300") // 5 minutes cache
+	if personalized {
+		c.Header("Cache-Control", "private, max-age=60")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300") // 5 minutes cache
+	}
+	c.Header("Vary", "X-Session-ID")
 	c.Header("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
 
 	log.Printf("Returned %d articles (page %d/%d) for region=%s in %v",
@@ -185,7 +211,9 @@ func statsHandler(c *gin.Context, db *mongo.Database) {
 	cursor.All(ctx, &stats)
 
 	c.JSON(http.StatusOK, gin.H{
-		"regionStats": stats,
-		"timestamp":   time.Now(),
+		"regionStats":  stats,
+		"relatedIndex": relatedService.Stats(),
+		"imageProxy":   imageProxy.Stats(),
+		"timestamp":    time.Now(),
 	})
 }
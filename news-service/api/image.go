@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"news-service/handler"
+	"news-service/imageproxy"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var imageProxy *imageproxy.Proxy
+
+// imageHandler serves a resized, re-encoded copy of a signed upstream image,
+// falling back to the region's default image when the upstream is unusable
+func imageHandler(c *gin.Context) {
+	config := imageProxy.Config()
+	if !config.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Image proxy is not configured"})
+		return
+	}
+
+	imageURL := c.Query("url")
+	width, err := strconv.Atoi(c.Query("w"))
+	if imageURL == "" || err != nil || !imageproxy.Verify(config.Secret, imageURL, width, c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid image signature"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.Timeout)
+	defer cancel()
+
+	img, err := imageProxy.Get(ctx, imageURL, width)
+	if err != nil {
+		log.Printf("Image proxy failed for %s: %v", imageURL, err)
+
+		fallback := handler.DefaultNewsImage(mapRegionToCode(c.Query("region")))
+		img, err = imageProxy.Get(ctx, fallback, width)
+		if err != nil {
+			log.Printf("Image proxy fallback failed for %s: %v", fallback, err)
+			c.Redirect(http.StatusFound, fallback)
+			return
+		}
+		// Upstream may recover; don't let clients pin the placeholder
+		c.Header("Cache-Control", "public, max-age=600")
+	} else {
+		c.Header("Cache-Control", "public, max-age=86400, immutable")
+	}
+
+	c.Header("ETag", img.ETag)
+	if c.GetHeader("If-None-Match") == img.ETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Last-Modified", img.CreatedAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
+
+// proxyArticleImages points article images at the proxy, keeping the
+// upstream URL in imageOriginal. It's a no-op while the proxy is disabled.
+func proxyArticleImages(articles []bson.M) {
+	config := imageProxy.Config()
+	if !config.Enabled() {
+		return
+	}
+
+	for _, article := range articles {
+		original, _ := article["image"].(string)
+		region, _ := article["topic"].(string)
+		if original == "" {
+			original = handler.DefaultNewsImage(region)
+		}
+		article["imageOriginal"] = original
+		article["image"] = config.URL(original, config.DefaultWidth, region)
+		article["thumbnail"] = config.URL(original, config.ThumbnailWidth, region)
+	}
+}
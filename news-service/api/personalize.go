@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"log"
+	"news-service/personalize"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var personalizer *personalize.Client
+
+// personalizeArticles re-ranks a page of articles for the caller's session.
+// Any failure leaves the chronological order untouched.
+func personalizeArticles(c *gin.Context, articles []bson.M) ([]bson.M, bool) {
+	sessionID := personalizer.SessionFor(c)
+	if sessionID == "" || len(articles) < 2 {
+		return articles, false
+	}
+
+	byID := make(map[string]bson.M, len(articles))
+	candidates := make([]personalize.Candidate, 0, len(articles))
+	for _, article := range articles {
+		id, ok := article["_id"].(primitive.ObjectID)
+		if !ok {
+			return articles, false
+		}
+		byID[id.Hex()] = article
+
+		candidate := personalize.Candidate{
+			ID:         id.Hex(),
+			Popularity: 0.5, // Articles carry no engagement data
+		}
+		candidate.Title, _ = article["title"].(string)
+		candidate.Region, _ = article["topic"].(string)
+		if source, ok := article["source"].(bson.M); ok {
+			candidate.Source, _ = source["name"].(string)
+		}
+		if published, ok := article["publishedAt"].(primitive.DateTime); ok {
+			candidate.PublishedAt = published.Time()
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second)
+	defer cancel()
+
+	rankings, personalized, err := personalizer.Rank(ctx, sessionID, candidates)
+	if err != nil {
+		log.Printf("Personalisation skipped: %v", err)
+		return articles, false
+	}
+	if len(rankings) != len(articles) {
+		return articles, false
+	}
+
+	explain := c.Query("explain") == "true"
+	ranked := make([]bson.M, 0, len(articles))
+	for _, ranking := range rankings {
+		article, ok := byID[ranking.ID]
+		if !ok {
+			return articles, false
+		}
+		if explain {
+			article["personalization"] = gin.H{
+				"score":       ranking.Score,
+				"explanation": ranking.Explanation,
+			}
+		}
+		ranked = append(ranked, article)
+	}
+	return ranked, personalized
+}
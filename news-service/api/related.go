@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"news-service/related"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var relatedService *related.Service
+
+// relatedHandler returns "more like this" articles for an article ID
+func relatedHandler(c *gin.Context) {
+	start := time.Now()
+	config := relatedService.Config()
+	id := c.Param("id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(config.DefaultLimit)))
+	if limit < 1 || limit > config.MaxLimit {
+		limit = config.DefaultLimit
+	}
+
+	query := related.Query{
+		Limit:  limit,
+		Region: mapRegionToCode(c.Query("region")),
+	}
+	if hours, err := strconv.Atoi(c.DefaultQuery("maxAgeHours", "72")); err == nil && hours > 0 {
+		query.MaxAge = time.Duration(hours) * time.Hour
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	articles, err := relatedService.Related(ctx, id, query)
+	if errors.Is(err, related.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Related articles query failed for %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Related articles query failed"})
+		return
+	}
+
+	if images := imageProxy.Config(); images.Enabled() {
+		for i := range articles {
+			articles[i].Image = images.URL(articles[i].Image, images.ThumbnailWidth, articles[i].Region)
+		}
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"articleId": id,
+		"related":   articles,
+		"metadata": gin.H{
+			"count":        len(articles),
+			"limit":        limit,
+			"region":       query.Region,
+			"maxAgeHours":  int(query.MaxAge.Hours()),
+			"responseTime": time.Since(start).String(),
+		},
+	})
+}
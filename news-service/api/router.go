@@ -1,10 +1,19 @@
 package api
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"news-service/entity"
+	"news-service/feed"
 	"news-service/handler"
+	"news-service/imageproxy"
 	"news-service/metrics"
+	"news-service/personalize"
+	"news-service/related"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +32,7 @@ func StartServer(db *mongo.Database, natsURL string) {
 	router.Use(metricsMiddleware())
 
 	dbmngo = db
+	ensureArticleIndexes(db)
 
 	// Initialize News Handler with the collection
 	natsNewsHandler = handler.NewNewsHandler(db.Collection("articles"))
@@ -30,6 +40,25 @@ func StartServer(db *mongo.Database, natsURL string) {
 	// Initialize Streaming API
 	streamingAPI = NewStreamingAPI(natsNewsHandler)
 
+	// Initialize the cross-content feed
+	feedService = feed.NewService(db.Collection("articles"), feed.LoadConfig())
+	personalizer = personalize.NewClient()
+
+	// Initialize the related-articles index; it fills in the background
+	relatedService = related.NewService(db.Collection("articles"), related.LoadConfig())
+	go relatedService.Start(context.Background())
+
+	// Extract entities from stored articles in the background
+	entityService = entity.NewService(db.Collection("articles"), entity.LoadConfig())
+	go entityService.Start(context.Background())
+
+	// Image proxy; article images are only rewritten when a signing secret is set
+	imageProxy = imageproxy.New(imageproxy.LoadConfig())
+	if !imageProxy.Config().Enabled() {
+		log.Println("IMAGE_PROXY_SECRET not set, image proxy disabled")
+	}
+	go imageProxy.Start(context.Background())
+
 	// Health check routes
 	router.GET("/", healthCheck)
 	router.GET("/health", healthCheck)
@@ -38,6 +67,11 @@ func StartServer(db *mongo.Database, natsURL string) {
 
 	// API routes
 	router.GET("/news-api/news", callnewsHandler)
+	router.GET("/news-api/search", searchHandler)
+	router.GET("/news-api/categories", categoriesHandler)
+	router.GET("/news-api/entities/trending", trendingEntitiesHandler)
+	router.GET("/news-api/entities/:name", entityHandler)
+	router.GET("/news-api/image", imageHandler)
 	router.GET("/news-api/regions", getRegions)
 	router.GET("/news-api/stats", getStats)
 	router.POST("/news-api/fetch/:region", triggerRegionFetch)
@@ -45,6 +79,12 @@ func StartServer(db *mongo.Database, natsURL string) {
 	router.DELETE("/news-api/cleanup/:region", cleanupRegionNews)
 	router.POST("/news-api/cleanup-refresh/:region", cleanupAndRefreshRegion)
 
+	router.GET("/news-api/articles/:id/related", relatedHandler)
+
+	// Unified scroll feed
+	router.GET("/news-api/feed", feedHandler)
+	router.GET("/feed", feedHandler)
+
 	// Streaming API routes
 	streamingRoutes := router.Group("/streaming-api")
 	{
@@ -59,10 +99,31 @@ func StartServer(db *mongo.Database, natsURL string) {
 	log.Println("News API is running at :80")
 	log.Println("Streaming API available at /streaming-api/*")
 
+	// Stop background work on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// Start the background fetcher
-	go handler.StartScheduledFetcher(db)
+	go handler.StartScheduledFetcher(ctx, db)
+
+	srv := &http.Server{Addr: ":80", Handler: router}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down news service...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+	natsNewsHandler.Close()
 
-	router.Run(":80")
+	log.Println("News service stopped")
 }
 
 func healthCheck(c *gin.Context) {
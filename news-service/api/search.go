@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ensureArticleIndexes creates the text index behind /news-api/search and the
+// indexes behind the lang and category filters
+func ensureArticleIndexes(db *mongo.Database) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			// Stemming is per-language in Mongo and Hindi isn't supported, so index
+			// raw terms. The override points at a field we never set so the
+			// article's own lang can't select an unsupported analyser.
+			Options: options.Index().
+				SetName("article_text").
+				SetWeights(bson.M{"title": 3, "description": 1}).
+				SetDefaultLanguage("none").
+				SetLanguageOverride("textLanguage"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "lang", Value: 1},
+				{Key: "topic", Value: 1},
+				{Key: "publishedAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "topic", Value: 1},
+				{Key: "category", Value: 1},
+				{Key: "publishedAt", Value: -1},
+			},
+		},
+	}
+
+	if _, err := db.Collection("articles").Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("Warning: Failed to create search indexes: %v", err)
+	}
+}
+
+// searchHandler runs a full-text query over article titles and descriptions
+func searchHandler(c *gin.Context) {
+	start := time.Now()
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	region := mapRegionToCode(c.Query("region"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"$text": bson.M{"$search": q}}
+	if region != "" {
+		filter["topic"] = region
+	}
+	if lang := inFilter(c.Query("lang")); lang != nil {
+		filter["lang"] = lang
+	}
+	if category := inFilter(c.Query("category")); category != nil {
+		filter["category"] = category
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{
+			"title":       1,
+			"description": 1,
+			"url":         1,
+			"image":       1,
+			"source":      1,
+			"publishedAt": 1,
+			"topic":       1,
+			"lang":        1,
+			"category":    1,
+			"score":       bson.M{"$meta": "textScore"},
+		}).
+		SetSort(bson.D{
+			{Key: "score", Value: bson.M{"$meta": "textScore"}},
+			{Key: "publishedAt", Value: -1},
+		}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	collection := dbmngo.Collection("articles")
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Search query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	results := []bson.M{}
+	if err := cursor.All(ctx, &results); err != nil {
+		log.Printf("Search cursor decode failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Data processing failed"})
+		return
+	}
+	proxyArticleImages(results)
+
+	totalCount, _ := collection.CountDocuments(ctx, filter)
+	totalPages := (int(totalCount) + limit - 1) / limit
+
+	log.Printf("Search q=%q region=%s lang=%s returned %d of %d in %v",
+		q, region, c.Query("lang"), len(results), totalCount, time.Since(start))
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles": results,
+		"metadata": gin.H{
+			"query":        q,
+			"page":         page,
+			"limit":        limit,
+			"total":        totalCount,
+			"totalPages":   totalPages,
+			"hasNext":      page < totalPages,
+			"hasPrev":      page > 1,
+			"region":       region,
+			"lang":         c.Query("lang"),
+			"category":     c.Query("category"),
+			"responseTime": time.Since(start).String(),
+		},
+	})
+}
+
+// inFilter turns "hi" or "en,hi" into a Mongo match value; nil means no filter
+func inFilter(value string) interface{} {
+	langs := splitList(strings.ToLower(value))
+	switch len(langs) {
+	case 0:
+		return nil
+	case 1:
+		return langs[0]
+	default:
+		return bson.M{"$in": langs}
+	}
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"news-service/handler"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -60,6 +62,18 @@ func (sa *StreamingAPI) GetStreamStatus(c *gin.Context) {
 		}
 	}
 
+	// Outbox backlog shows events written to Mongo but not yet relayed
+	if sa.newsHandler != nil && sa.newsHandler.GetOutbox() != nil {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		outboxStatus := map[string]interface{}{"enabled": true}
+		if stats, err := sa.newsHandler.GetOutbox().Stats(ctx); err == nil {
+			outboxStatus["records"] = stats
+		}
+		status["services"].(map[string]interface{})["outbox"] = outboxStatus
+	}
+
 	c.JSON(http.StatusOK, status)
 }
 
@@ -0,0 +1,137 @@
+// Package classify assigns a news category to articles offline, combining
+// keyword rules with a naive-Bayes model shipped alongside the code.
+package classify
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// General is assigned when no category is confident enough
+const General = "general"
+
+// minConfidence is the probability a category needs to be assigned
+const minConfidence = 0.35
+
+//go:embed model.json
+var embeddedModel []byte
+
+// Result is a category with the posterior probability behind it
+type Result struct {
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Classifier combines keyword rules with a naive-Bayes model
+type Classifier struct {
+	model *Model
+}
+
+// New loads the model from CLASSIFIER_MODEL_PATH, or the embedded model when unset
+func New() (*Classifier, error) {
+	data := embeddedModel
+	if path := os.Getenv("CLASSIFIER_MODEL_PATH"); path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read classifier model: %w", err)
+		}
+	}
+
+	model, err := LoadModel(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &Classifier{model: model}, nil
+}
+
+var defaultClassifier = func() *Classifier {
+	c, err := New()
+	if err != nil {
+		log.Printf("Failed to load classifier model, using embedded model: %v", err)
+		model, _ := LoadModel(bytes.NewReader(embeddedModel))
+		c = &Classifier{model: model}
+	}
+	return c
+}()
+
+// ForArticle classifies an article's title and description with the default classifier
+func ForArticle(title, description string) Result {
+	return defaultClassifier.Classify(title + ". " + description)
+}
+
+// Categories lists the categories the classifier can assign, plus General
+func Categories() []string {
+	return append(append([]string{}, defaultClassifier.model.Categories...), General)
+}
+
+// Classify returns the most probable category for text
+func (c *Classifier) Classify(text string) Result {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return Result{Category: General}
+	}
+
+	scores := c.model.LogScores(tokens)
+	for category, hits := range ruleHits(tokens) {
+		if _, ok := scores[category]; ok {
+			scores[category] += ruleBoost * float64(hits)
+		}
+	}
+
+	// Normalise log scores into probabilities
+	categories := make([]string, 0, len(scores))
+	max := math.Inf(-1)
+	for category, score := range scores {
+		categories = append(categories, category)
+		max = math.Max(max, score)
+	}
+	sort.Strings(categories)
+
+	sum := 0.0
+	for _, category := range categories {
+		sum += math.Exp(scores[category] - max)
+	}
+
+	best, bestProb := General, 0.0
+	for _, category := range categories {
+		if prob := math.Exp(scores[category]-max) / sum; prob > bestProb {
+			best, bestProb = category, prob
+		}
+	}
+
+	bestProb = math.Round(bestProb*1000) / 1000
+	if bestProb < minConfidence {
+		return Result{Category: General, Confidence: bestProb}
+	}
+	return Result{Category: best, Confidence: bestProb}
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "that": true,
+	"this": true, "are": true, "was": true, "has": true, "have": true, "its": true,
+	"after": true, "over": true, "into": true, "about": true, "new": true, "says": true,
+	"der": true, "die": true, "das": true, "und": true, "mit": true, "von": true,
+	"के": true, "की": true, "का": true, "में": true, "ने": true, "को": true, "से": true, "है": true,
+}
+
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) && !unicode.Is(unicode.Mc, r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 2 || stopWords[field] {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
@@ -0,0 +1,141 @@
+package classify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Model is a multinomial naive-Bayes model stored as log probabilities
+type Model struct {
+	Version     int                           `json:"version"`
+	Categories  []string                      `json:"categories"`
+	Priors      map[string]float64            `json:"priors"`
+	Likelihoods map[string]map[string]float64 `json:"likelihoods"`
+	Unseen      map[string]float64            `json:"unseen"` // Log probability of a token absent from a category
+}
+
+// Example is one labelled training text
+type Example struct {
+	Category string
+	Text     string
+}
+
+// ReadExamples parses "category<TAB>text" lines, skipping blanks and # comments
+func ReadExamples(r io.Reader) ([]Example, error) {
+	var examples []Example
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		category, body, ok := strings.Cut(text, "\t")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected category<TAB>text", line)
+		}
+		examples = append(examples, Example{Category: strings.TrimSpace(category), Text: body})
+	}
+	return examples, scanner.Err()
+}
+
+// Train fits a model with Laplace smoothing
+func Train(examples []Example) *Model {
+	docCounts := make(map[string]int)
+	tokenCounts := make(map[string]map[string]float64)
+	totals := make(map[string]float64)
+	vocabulary := make(map[string]bool)
+
+	for _, example := range examples {
+		docCounts[example.Category]++
+		if tokenCounts[example.Category] == nil {
+			tokenCounts[example.Category] = make(map[string]float64)
+		}
+		for _, token := range tokenize(example.Text) {
+			tokenCounts[example.Category][token]++
+			totals[example.Category]++
+			vocabulary[token] = true
+		}
+	}
+
+	model := &Model{
+		Version:     1,
+		Priors:      make(map[string]float64),
+		Likelihoods: make(map[string]map[string]float64),
+		Unseen:      make(map[string]float64),
+	}
+
+	v := float64(len(vocabulary))
+	for category, count := range docCounts {
+		model.Categories = append(model.Categories, category)
+		model.Priors[category] = math.Log(float64(count) / float64(len(examples)))
+
+		denominator := totals[category] + v
+		model.Likelihoods[category] = make(map[string]float64, len(tokenCounts[category]))
+		for token, n := range tokenCounts[category] {
+			model.Likelihoods[category][token] = round(math.Log((n + 1) / denominator))
+		}
+		model.Unseen[category] = round(math.Log(1 / denominator))
+	}
+	sort.Strings(model.Categories)
+	return model
+}
+
+// LoadModel reads a model written by Train
+func LoadModel(r io.Reader) (*Model, error) {
+	var model Model
+	if err := json.NewDecoder(r).Decode(&model); err != nil {
+		return nil, fmt.Errorf("failed to decode model: %w", err)
+	}
+	if len(model.Categories) == 0 {
+		return nil, fmt.Errorf("model has no categories")
+	}
+	return &model, nil
+}
+
+// Save writes the model as indented JSON so diffs stay reviewable
+func (m *Model) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(m)
+}
+
+// LogScores returns each category's unnormalised log posterior for tokens.
+// Tokens unknown to every category carry no evidence and are skipped.
+func (m *Model) LogScores(tokens []string) map[string]float64 {
+	scores := make(map[string]float64, len(m.Categories))
+	for _, category := range m.Categories {
+		scores[category] = m.Priors[category]
+	}
+
+	for _, token := range tokens {
+		known := false
+		for _, category := range m.Categories {
+			if _, ok := m.Likelihoods[category][token]; ok {
+				known = true
+				break
+			}
+		}
+		if !known {
+			continue
+		}
+		for _, category := range m.Categories {
+			if p, ok := m.Likelihoods[category][token]; ok {
+				scores[category] += p
+			} else {
+				scores[category] += m.Unseen[category]
+			}
+		}
+	}
+	return scores
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,76 @@
+package classify
+
+// Keyword rules catch unambiguous terms the small model may not have seen.
+// Each hit adds ruleBoost to the category's log score, so two hits outweigh
+// moderate model evidence but a single hit can still be overruled.
+const ruleBoost = 1.5
+
+var keywordRules = map[string][]string{
+	"politics": {
+		"election", "elections", "parliament", "minister", "senate", "congress",
+		"lawmakers", "opposition", "coalition", "cabinet", "governor", "campaign",
+		"referendum", "bundestag", "kanzler", "wahl", "landtag", "चुनाव", "संसद",
+		"मुख्यमंत्री", "प्रधानमंत्री", "विधानसभा", "bjp", "democrats", "republicans",
+	},
+	"business": {
+		"stocks", "sensex", "nifty", "dax", "inflation", "gdp", "ipo", "earnings",
+		"revenue", "profit", "shares", "investors", "rbi", "fed", "ezb", "aktien",
+		"economy", "tariff", "tariffs", "startup", "funding", "merger", "acquisition",
+		"शेयर", "सेंसेक्स", "महंगाई", "बाजार",
+	},
+	"tech": {
+		"iphone", "android", "smartphone", "software", "ai", "chatbot", "cyber",
+		"hackers", "ransomware", "semiconductor", "chip", "chips", "google", "apple",
+		"microsoft", "openai", "meta", "app", "apps", "internet", "datenschutz",
+		"स्मार्टफोन", "साइबर",
+	},
+	"sports": {
+		"cricket", "football", "soccer", "tennis", "match", "tournament", "wickets",
+		"century", "innings",
+		"goal", "league", "olympic", "olympics", "ipl", "fifa", "nba", "nfl",
+		"bundesliga", "championship", "medal", "coach", "striker", "विकेट", "क्रिकेट",
+		"मैच", "ओलंपिक",
+	},
+	"entertainment": {
+		"film", "movie", "actor", "actress", "bollywood", "hollywood", "album",
+		"netflix", "trailer", "oscars", "grammy", "box", "celebrity", "singer",
+		"series", "kino", "schauspieler", "फिल्म", "अभिनेता", "गाना",
+	},
+	"health": {
+		"vaccine", "covid", "hospital", "disease", "dengue", "cancer", "doctors",
+		"virus", "outbreak", "who", "patients", "krankenhaus", "gesundheit",
+		"अस्पताल", "डॉक्टर", "डेंगू",
+	},
+	"science": {
+		"nasa", "isro", "spacecraft", "satellite", "astronomers", "galaxy", "mars",
+		"moon", "lunar", "scientists", "researchers", "fossil", "species",
+		"earthquake", "climate", "इसरो", "वैज्ञानिकों",
+	},
+}
+
+// ruleIndex maps keyword -> category for O(1) lookups
+var ruleIndex = func() map[string]string {
+	index := make(map[string]string)
+	for category, keywords := range keywordRules {
+		for _, keyword := range keywords {
+			index[keyword] = category
+		}
+	}
+	return index
+}()
+
+// ruleHits counts keyword matches per category
+func ruleHits(tokens []string) map[string]int {
+	hits := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if category, ok := ruleIndex[token]; ok {
+			hits[category]++
+		}
+	}
+	return hits
+}
@@ -0,0 +1,42 @@
+// train-classifier rebuilds classify/model.json from labelled examples.
+//
+//	go run ./cmd/train-classifier -in classify/training.tsv -out classify/model.json
+package main
+
+import (
+	"flag"
+	"log"
+	"news-service/classify"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "classify/training.tsv", "labelled examples, one category<TAB>text per line")
+	out := flag.String("out", "classify/model.json", "where to write the model")
+	flag.Parse()
+
+	file, err := os.Open(*in)
+	if err != nil {
+		log.Fatal("Failed to open training data:", err)
+	}
+	defer file.Close()
+
+	examples, err := classify.ReadExamples(file)
+	if err != nil {
+		log.Fatal("Failed to read training data:", err)
+	}
+
+	model := classify.Train(examples)
+
+	output, err := os.Create(*out)
+	if err != nil {
+		log.Fatal("Failed to create model file:", err)
+	}
+	defer output.Close()
+
+	if err := model.Save(output); err != nil {
+		log.Fatal("Failed to write model:", err)
+	}
+
+	log.Printf("Trained %d categories from %d examples -> %s", len(model.Categories), len(examples), *out)
+}
@@ -0,0 +1,324 @@
+// Package entity extracts people, organisations and places from articles and
+// keeps the entities collection with aliases, mention counts and timelines.
+package entity
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// Entity types
+const (
+	TypePerson       = "person"
+	TypeOrganization = "organization"
+	TypePlace        = "place"
+)
+
+// Mention is an entity found in a piece of text
+type Mention struct {
+	ID      string `json:"id" bson:"id"` // Slug of the canonical name
+	Name    string `json:"name" bson:"name"`
+	Type    string `json:"type" bson:"type"`
+	Surface string `json:"-" bson:"-"` // Form as written, recorded as an alias
+}
+
+type gazetteerEntry struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Aliases []string `json:"aliases"`
+}
+
+//go:embed gazetteer.json
+var gazetteerData []byte
+
+// gazetteer maps an alias, as a space-joined token sequence, to its entry.
+// Matching is case-sensitive so "Apple" the company isn't found in "apple pie".
+var (
+	gazetteer       map[string]gazetteerEntry
+	maxAliasTokens  int
+	gazetteerByName map[string]gazetteerEntry
+)
+
+func init() {
+	var entries []gazetteerEntry
+	if err := json.Unmarshal(gazetteerData, &entries); err != nil {
+		log.Printf("Failed to load entity gazetteer: %v", err)
+	}
+
+	gazetteer = make(map[string]gazetteerEntry)
+	gazetteerByName = make(map[string]gazetteerEntry)
+	for _, entry := range entries {
+		gazetteerByName[Slug(entry.Name)] = entry
+		for _, alias := range append([]string{entry.Name}, entry.Aliases...) {
+			words := words(alias)
+			if len(words) == 0 {
+				continue
+			}
+			gazetteer[strings.Join(words, " ")] = entry
+			if len(words) > maxAliasTokens {
+				maxAliasTokens = len(words)
+			}
+		}
+	}
+}
+
+// Slug returns the entity ID for a name: lowercase words joined by hyphens
+func Slug(name string) string {
+	return strings.Join(words(strings.ToLower(name)), "-")
+}
+
+// Words that may sit inside a multi-word name ("Bank of America", "Ursula von der Leyen")
+var connectors = map[string]bool{
+	"of": true, "de": true, "von": true, "van": true, "der": true, "den": true,
+	"al": true, "bin": true, "da": true, "la": true, "and": true, "für": true,
+}
+
+// Titles preceding a person's name
+var personCues = map[string]bool{
+	"Mr": true, "Mrs": true, "Ms": true, "Dr": true, "President": true, "Minister": true,
+	"PM": true, "Senator": true, "Governor": true, "Chancellor": true, "CEO": true,
+	"Judge": true, "Justice": true, "Prince": true, "Princess": true, "King": true,
+	"Queen": true, "Pope": true, "Coach": true, "Captain": true, "Actor": true,
+	"Actress": true, "Singer": true, "Herr": true, "Frau": true, "Kanzler": true,
+	"Kanzlerin": true, "Präsident": true, "Präsidentin": true, "Ministerin": true,
+	"Bundeskanzler": true, "Chef": true, "Chefin": true,
+}
+
+// Verbs that follow a person's name
+var personVerbs = map[string]bool{
+	"said": true, "says": true, "told": true, "claimed": true, "announced": true,
+	"sagte": true, "sagt": true, "erklärte": true,
+}
+
+// Last words that mark organisations
+var organizationSuffixes = map[string]bool{
+	"Inc": true, "Corp": true, "Corporation": true, "Ltd": true, "LLC": true, "Group": true,
+	"Bank": true, "University": true, "Party": true, "Ministry": true, "Council": true,
+	"Court": true, "Commission": true, "Agency": true, "Association": true, "Federation": true,
+	"Institute": true, "Airlines": true, "Motors": true, "Technologies": true,
+	"Foundation": true, "FC": true, "Club": true, "Committee": true, "Department": true,
+	"GmbH": true, "AG": true, "SE": true, "Partei": true, "Universität": true,
+	"Ministerium": true, "Gericht": true, "Verband": true, "Stiftung": true,
+}
+
+// Last words that mark places
+var placeSuffixes = map[string]bool{
+	"City": true, "State": true, "Province": true, "District": true, "River": true,
+	"Island": true, "Islands": true, "Valley": true, "County": true, "Nagar": true,
+	"Pradesh": true, "Stadt": true, "Kreis": true,
+}
+
+// Capitalised at sentence start but never the start of a name
+var determiners = map[string]bool{
+	"A": true, "An": true, "This": true, "That": true, "These": true, "Those": true,
+	"Der": true, "Die": true, "Das": true, "Ein": true, "Eine": true,
+}
+
+// Prepositions that precede places
+var placePrepositions = map[string]map[string]bool{
+	"en": {"in": true, "at": true, "from": true, "near": true},
+	"de": {"in": true, "aus": true, "nach": true, "bei": true},
+}
+
+type token struct {
+	text          string
+	sentenceStart bool
+	sentence      int
+}
+
+// Extract finds entities in text. lang selects the capitalisation rules:
+// English uses capitalised spans with cues; German, where every noun is
+// capitalised, needs a cue or a multi-word span with a cue; other languages
+// and scripts rely on the gazetteer alone.
+func Extract(text, lang string) []Mention {
+	tokens := tokenize(text)
+	seen := make(map[string]bool)
+	var mentions []Mention
+
+	add := func(m Mention) {
+		if m.ID == "" || seen[m.ID] {
+			return
+		}
+		seen[m.ID] = true
+		mentions = append(mentions, m)
+	}
+
+	heuristics := lang == "en" || lang == "de"
+	titleCase := titleCaseSentences(tokens)
+
+	for i := 0; i < len(tokens); {
+		// Gazetteer: longest alias first
+		matched := 0
+		for n := min(maxAliasTokens, len(tokens)-i); n > 0; n-- {
+			if entry, ok := gazetteer[joinTokens(tokens[i:i+n])]; ok {
+				add(Mention{ID: Slug(entry.Name), Name: entry.Name, Type: entry.Type, Surface: joinTokens(tokens[i : i+n])})
+				matched = n
+				break
+			}
+		}
+		if matched > 0 {
+			i += matched
+			continue
+		}
+
+		if !heuristics || titleCase[tokens[i].sentence] || !isCapitalized(tokens[i].text) {
+			i++
+			continue
+		}
+
+		// Titles and capitalised determiners aren't part of a name; step past
+		// them so the gazetteer gets a chance at the following words
+		if personCues[tokens[i].text] || titleCaseWords[tokens[i].text] || determiners[tokens[i].text] {
+			i++
+			continue
+		}
+
+		end := spanEnd(tokens, i)
+		if typ := inferType(tokens, i, end, lang); typ != "" {
+			name := joinTokens(tokens[i:end])
+			add(Mention{ID: Slug(name), Name: name, Type: typ, Surface: name})
+		}
+		i = end
+	}
+	return mentions
+}
+
+// spanEnd returns the end of the capitalised span starting at i, allowing
+// lowercase connectors between capitalised words
+func spanEnd(tokens []token, i int) int {
+	end := i + 1
+	for end < len(tokens) && !tokens[end].sentenceStart {
+		if isCapitalized(tokens[end].text) {
+			end++
+			continue
+		}
+		if connectors[tokens[end].text] && end+1 < len(tokens) && isCapitalized(tokens[end+1].text) && !tokens[end+1].sentenceStart {
+			end += 2
+			continue
+		}
+		break
+	}
+	return end
+}
+
+// inferType assigns a type to tokens[start:end] from its surroundings, or ""
+// when there isn't enough evidence
+func inferType(tokens []token, start, end int, lang string) string {
+	span := tokens[start:end]
+	last := span[len(span)-1].text
+
+	if organizationSuffixes[last] && len(span) > 1 {
+		return TypeOrganization
+	}
+	if placeSuffixes[last] && len(span) > 1 {
+		return TypePlace
+	}
+
+	if start > 0 && personCues[tokens[start-1].text] && len(span) <= 3 {
+		return TypePerson
+	}
+	if end < len(tokens) && personVerbs[tokens[end].text] && len(span) >= 2 && len(span) <= 3 {
+		return TypePerson
+	}
+
+	// German capitalises all nouns, so prepositions alone aren't a place cue
+	if lang == "en" && start > 0 && !span[0].sentenceStart && placePrepositions[lang][tokens[start-1].text] && len(span) <= 2 {
+		return TypePlace
+	}
+	return ""
+}
+
+// Short words headline style capitalises but running text doesn't
+var titleCaseWords = map[string]bool{
+	"The": true, "In": true, "As": true, "Of": true, "To": true, "For": true,
+	"And": true, "On": true, "At": true, "With": true, "By": true, "From": true,
+	"Is": true, "Are": true, "After": true, "Over": true,
+}
+
+// titleCaseSentences marks headline-style sentences where capitalisation
+// carries no signal; only the gazetteer applies to them
+func titleCaseSentences(tokens []token) map[int]bool {
+	functionWords := make(map[int]int)
+	for _, t := range tokens {
+		if !t.sentenceStart && titleCaseWords[t.text] {
+			functionWords[t.sentence]++
+		}
+	}
+
+	result := make(map[int]bool)
+	for sentence, count := range functionWords {
+		result[sentence] = count >= 2
+	}
+	return result
+}
+
+func isCapitalized(word string) bool {
+	for _, r := range word {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+func joinTokens(tokens []token) string {
+	parts := make([]string, len(tokens))
+	for i, t := range tokens {
+		parts[i] = t.text
+	}
+	return strings.Join(parts, " ")
+}
+
+// tokenize splits text into words, marking the first word of each sentence.
+// Possessive 's is dropped so "Modi's" matches "Modi".
+func tokenize(text string) []token {
+	var tokens []token
+	sentenceStart := true
+	sentence := 0
+	var current []rune
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		word := strings.TrimSuffix(strings.TrimSuffix(string(current), "'s"), "’s")
+		word = strings.Trim(word, "'’.")
+		if word != "" {
+			tokens = append(tokens, token{text: word, sentenceStart: sentenceStart, sentence: sentence})
+			sentenceStart = false
+		}
+		current = current[:0]
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r):
+			current = append(current, r)
+		case (r == '\'' || r == '’' || r == '.') && len(current) > 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			// Keep apostrophes and dots inside words ("O'Brien", "U.S")
+			current = append(current, r)
+		default:
+			flush()
+			if r == '.' || r == '!' || r == '?' || r == ':' || r == '|' || r == '।' || r == '\n' {
+				if !sentenceStart {
+					sentence++
+				}
+				sentenceStart = true
+			}
+		}
+	}
+	flush()
+	return tokens
+}
+
+// words splits an alias the same way tokenize splits text
+func words(text string) []string {
+	tokens := tokenize(text)
+	result := make([]string, len(tokens))
+	for i, t := range tokens {
+		result[i] = t.text
+	}
+	return result
+}
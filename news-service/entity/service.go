@@ -0,0 +1,443 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"news-service/langdetect"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExtractorVersion is stored on enriched articles; bump it to re-extract
+// everything after changing the rules or gazetteer
+const ExtractorVersion = 1
+
+// ErrNotFound is returned when no entity matches a name or alias
+var ErrNotFound = errors.New("entity not found")
+
+// Config controls the enrichment loop
+type Config struct {
+	Interval  time.Duration // Pause between enrichment passes
+	BatchSize int64         // Articles enriched per query
+	Lookback  time.Duration // Only articles fetched this recently are enriched
+}
+
+// LoadConfig reads entity extraction configuration from the environment
+func LoadConfig() *Config {
+	return &Config{
+		Interval:  time.Duration(getEnvIntOrDefault("ENTITY_INTERVAL_SECONDS", 30)) * time.Second,
+		BatchSize: int64(getEnvIntOrDefault("ENTITY_BATCH_SIZE", 200)),
+		Lookback:  time.Duration(getEnvIntOrDefault("ENTITY_LOOKBACK_HOURS", 72)) * time.Hour,
+	}
+}
+
+// Entity is a document in the entities collection
+type Entity struct {
+	ID           string    `json:"id" bson:"_id"`
+	Name         string    `json:"name" bson:"name"`
+	Type         string    `json:"type" bson:"type"`
+	Aliases      []string  `json:"aliases" bson:"aliases"`
+	AliasKeys    []string  `json:"-" bson:"aliasKeys"` // Slugs of aliases for lookup
+	MentionCount int64     `json:"mentionCount" bson:"mentionCount"`
+	FirstSeen    time.Time `json:"firstSeen" bson:"firstSeen"`
+	LastSeen     time.Time `json:"lastSeen" bson:"lastSeen"`
+}
+
+// TimelinePoint is the number of mentions on one day
+type TimelinePoint struct {
+	Date     string `json:"date" bson:"_id"`
+	Mentions int64  `json:"mentions" bson:"mentions"`
+}
+
+// Trending is an entity's mention count in the current and previous window
+type Trending struct {
+	ID       string  `json:"id" bson:"_id"`
+	Name     string  `json:"name" bson:"name"`
+	Type     string  `json:"type" bson:"type"`
+	Mentions int64   `json:"mentions" bson:"mentions"`
+	Previous int64   `json:"previous" bson:"previous"`
+	Growth   float64 `json:"growth" bson:"-"`
+}
+
+type articleDoc struct {
+	Title       string    `bson:"title"`
+	Description string    `bson:"description"`
+	URL         string    `bson:"url"`
+	Topic       string    `bson:"topic"`
+	Lang        string    `bson:"lang"`
+	PublishedAt time.Time `bson:"publishedAt"`
+}
+
+// Service enriches articles with entities and answers entity queries
+type Service struct {
+	articles *mongo.Collection
+	entities *mongo.Collection
+	mentions *mongo.Collection
+	config   *Config
+}
+
+// NewService uses the entities and entity_mentions collections beside articles
+func NewService(articles *mongo.Collection, config *Config) *Service {
+	db := articles.Database()
+	s := &Service{
+		articles: articles,
+		entities: db.Collection("entities"),
+		mentions: db.Collection("entity_mentions"),
+		config:   config,
+	}
+	s.ensureIndexes()
+	return s
+}
+
+func (s *Service) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := s.mentions.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// One mention per entity per article, so re-enrichment is idempotent
+			Keys:    bson.D{{Key: "entityId", Value: 1}, {Key: "articleUrl", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "entityId", Value: 1}, {Key: "publishedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "publishedAt", Value: -1}, {Key: "region", Value: 1}}},
+	}); err != nil {
+		log.Printf("Warning: Failed to create entity mention indexes: %v", err)
+	}
+
+	if _, err := s.entities.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "aliasKeys", Value: 1}},
+	}); err != nil {
+		log.Printf("Warning: Failed to create entity alias index: %v", err)
+	}
+
+	if _, err := s.articles.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "entities.id", Value: 1}, {Key: "publishedAt", Value: -1}},
+	}); err != nil {
+		log.Printf("Warning: Failed to create article entity index: %v", err)
+	}
+}
+
+// ForArticle extracts entities from an article, detecting its language when
+// it wasn't recorded at ingest
+func ForArticle(title, description, lang, region string) []Mention {
+	if lang == "" {
+		lang = langdetect.ForArticle(title, description, region)
+	}
+	return Extract(title+". "+description, lang)
+}
+
+// Start enriches articles that haven't been through the current extractor.
+// Running as a stage over the collection covers both news-service and
+// news-fetcher-service ingest without either having to call it.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			enriched, err := s.enrichBatch(ctx)
+			if err != nil {
+				log.Printf("Entity enrichment failed: %v", err)
+				break
+			}
+			if enriched < int(s.config.BatchSize) {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) enrichBatch(ctx context.Context) (int, error) {
+	batchCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	filter := bson.M{
+		"fetchedAt":     bson.M{"$gte": time.Now().Add(-s.config.Lookback)},
+		"entityVersion": bson.M{"$ne": ExtractorVersion},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "fetchedAt", Value: -1}}).
+		SetLimit(s.config.BatchSize).
+		SetProjection(bson.M{"title": 1, "description": 1, "url": 1, "topic": 1, "lang": 1, "publishedAt": 1})
+
+	cursor, err := s.articles.Find(batchCtx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer cursor.Close(batchCtx)
+
+	var articles []articleDoc
+	if err := cursor.All(batchCtx, &articles); err != nil {
+		return 0, fmt.Errorf("failed to decode articles: %w", err)
+	}
+
+	for _, article := range articles {
+		if err := s.enrich(batchCtx, article); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(articles) > 0 {
+		log.Printf("Extracted entities for %d articles", len(articles))
+	}
+	return len(articles), nil
+}
+
+func (s *Service) enrich(ctx context.Context, article articleDoc) error {
+	mentions := ForArticle(article.Title, article.Description, article.Lang, article.Topic)
+
+	refs := make([]bson.M, 0, len(mentions))
+	for _, m := range mentions {
+		refs = append(refs, bson.M{"id": m.ID, "name": m.Name, "type": m.Type})
+		if err := s.recordMention(ctx, m, article); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.articles.UpdateOne(ctx, bson.M{"url": article.URL}, bson.M{
+		"$set": bson.M{"entities": refs, "entityVersion": ExtractorVersion},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store entities on article: %w", err)
+	}
+	return nil
+}
+
+// recordMention links the article to the entity and keeps the entity's
+// counts and aliases current. Counts only move when the mention is new.
+func (s *Service) recordMention(ctx context.Context, m Mention, article articleDoc) error {
+	seenAt := article.PublishedAt
+	if seenAt.IsZero() {
+		seenAt = time.Now()
+	}
+
+	result, err := s.mentions.UpdateOne(ctx,
+		bson.M{"entityId": m.ID, "articleUrl": article.URL},
+		bson.M{"$setOnInsert": bson.M{
+			"entityId":    m.ID,
+			"articleUrl":  article.URL,
+			"region":      article.Topic,
+			"publishedAt": seenAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record mention: %w", err)
+	}
+
+	aliases := []string{m.Name}
+	if m.Surface != "" && m.Surface != m.Name {
+		aliases = append(aliases, m.Surface)
+	}
+	if entry, ok := gazetteerByName[m.ID]; ok {
+		aliases = append(aliases, entry.Aliases...)
+	}
+	aliasKeys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		aliasKeys = append(aliasKeys, Slug(alias))
+	}
+
+	update := bson.M{
+		"$setOnInsert": bson.M{"name": m.Name, "type": m.Type},
+		"$addToSet": bson.M{
+			"aliases":   bson.M{"$each": aliases},
+			"aliasKeys": bson.M{"$each": aliasKeys},
+		},
+	}
+	if result.UpsertedCount > 0 {
+		update["$inc"] = bson.M{"mentionCount": 1}
+		update["$min"] = bson.M{"firstSeen": seenAt}
+		update["$max"] = bson.M{"lastSeen": seenAt}
+	}
+
+	if _, err := s.entities.UpdateOne(ctx, bson.M{"_id": m.ID}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to update entity %s: %w", m.ID, err)
+	}
+	return nil
+}
+
+// Lookup finds an entity by canonical name, slug or alias
+func (s *Service) Lookup(ctx context.Context, name string) (*Entity, error) {
+	key := Slug(name)
+	if key == "" {
+		return nil, ErrNotFound
+	}
+
+	var entity Entity
+	err := s.entities.FindOne(ctx, bson.M{
+		"$or": []bson.M{{"_id": key}, {"aliasKeys": key}},
+	}, options.FindOne().SetSort(bson.D{{Key: "mentionCount", Value: -1}})).Decode(&entity)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up entity: %w", err)
+	}
+	return &entity, nil
+}
+
+// RecentArticles returns the latest articles mentioning an entity
+func (s *Service) RecentArticles(ctx context.Context, id, region string, limit int64) ([]bson.M, error) {
+	filter := bson.M{"entities.id": id}
+	if region != "" {
+		filter["topic"] = region
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{
+			"title": 1, "description": 1, "url": 1, "image": 1, "source": 1,
+			"publishedAt": 1, "topic": 1, "lang": 1, "category": 1, "entities": 1,
+		})
+
+	cursor, err := s.articles.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entity articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	articles := []bson.M{}
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, fmt.Errorf("failed to decode entity articles: %w", err)
+	}
+	return articles, nil
+}
+
+// Timeline returns daily mention counts for an entity over the last days
+func (s *Service) Timeline(ctx context.Context, id, region string, days int) ([]TimelinePoint, error) {
+	match := bson.M{
+		"entityId":    id,
+		"publishedAt": bson.M{"$gte": time.Now().AddDate(0, 0, -days)},
+	}
+	if region != "" {
+		match["region"] = region
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":      bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$publishedAt"}},
+			"mentions": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+
+	cursor, err := s.mentions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate timeline: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	timeline := []TimelinePoint{}
+	if err := cursor.All(ctx, &timeline); err != nil {
+		return nil, fmt.Errorf("failed to decode timeline: %w", err)
+	}
+	return timeline, nil
+}
+
+// TrendingEntities ranks entities by mentions in the last window, with growth
+// against the window before it so rising names surface above perennial ones
+func (s *Service) TrendingEntities(ctx context.Context, region, typ string, window time.Duration, limit int) ([]Trending, error) {
+	now := time.Now()
+	current := now.Add(-window)
+	previous := current.Add(-window)
+
+	match := bson.M{"publishedAt": bson.M{"$gte": previous, "$lte": now}}
+	if region != "" {
+		match["region"] = region
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id": "$entityId",
+			"mentions": bson.M{"$sum": bson.M{
+				"$cond": []interface{}{bson.M{"$gte": []interface{}{"$publishedAt", current}}, 1, 0},
+			}},
+			"previous": bson.M{"$sum": bson.M{
+				"$cond": []interface{}{bson.M{"$lt": []interface{}{"$publishedAt", current}}, 1, 0},
+			}},
+		}},
+		{"$match": bson.M{"mentions": bson.M{"$gt": 0}}},
+		{"$lookup": bson.M{
+			"from":         s.entities.Name(),
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "entity",
+		}},
+		{"$unwind": "$entity"},
+		{"$addFields": bson.M{"name": "$entity.name", "type": "$entity.type"}},
+	}
+	if typ != "" {
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"type": typ}})
+	}
+	pipeline = append(pipeline,
+		bson.M{"$sort": bson.M{"mentions": -1, "_id": 1}},
+		// Leave room to re-order by growth
+		bson.M{"$limit": limit * 3},
+	)
+
+	cursor, err := s.mentions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate trending entities: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var trending []Trending
+	if err := cursor.All(ctx, &trending); err != nil {
+		return nil, fmt.Errorf("failed to decode trending entities: %w", err)
+	}
+
+	// Score mentions damped by how much of the volume was already there
+	for i := range trending {
+		trending[i].Growth = float64(trending[i].Mentions+1) / float64(trending[i].Previous+1)
+	}
+	sortTrending(trending)
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	if trending == nil {
+		trending = []Trending{}
+	}
+	return trending, nil
+}
+
+// sortTrending orders by mentions weighted by growth, ties by ID
+func sortTrending(trending []Trending) {
+	score := func(t Trending) float64 {
+		return float64(t.Mentions) * math.Sqrt(t.Growth)
+	}
+	sort.SliceStable(trending, func(i, j int) bool {
+		si, sj := score(trending[i]), score(trending[j])
+		if si != sj {
+			return si > sj
+		}
+		return trending[i].ID < trending[j].ID
+	})
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
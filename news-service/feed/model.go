@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Content types that can appear in the feed
+const (
+	TypeArticle = "article"
+	TypeVideo   = "video"
+	TypeViral   = "viral"
+	TypeMeme    = "meme"
+)
+
+// AllTypes lists every content type in default display order
+var AllTypes = []string{TypeArticle, TypeVideo, TypeViral, TypeMeme}
+
+// FeedItem is the common shape every content type is normalised into
+type FeedItem struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	URL         string                 `json:"url"`
+	Image       string                 `json:"image,omitempty"`
+	Source      string                 `json:"source"`
+	Region      string                 `json:"region,omitempty"`
+	Category    string                 `json:"category,omitempty"`
+	PublishedAt time.Time              `json:"publishedAt,omitempty"`
+	Score       float64                `json:"score"` // Popularity normalised to 0..1
+	Rank        float64                `json:"rank"`  // Blend of recency and score used for ordering
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Query describes one feed page request
+type Query struct {
+	Limit          int
+	Mix            map[string]int
+	Sort           string
+	Types          []string
+	Region         string
+	Categories     []string
+	ExcludeSources []string
+	Cursor         *Cursor
+}
+
+// Cursor pins the ranking time and records how far into each type's list
+// the client has scrolled
+type Cursor struct {
+	AsOf    time.Time      `json:"t"`
+	Offsets map[string]int `json:"o"`
+}
+
+// Encode returns the opaque cursor string handed to clients
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if cursor.Offsets == nil {
+		cursor.Offsets = make(map[string]int)
+	}
+	return &cursor, nil
+}
+
+// Page is a single feed response
+type Page struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+	HasMore    bool       `json:"hasMore"`
+}
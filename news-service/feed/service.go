@@ -0,0 +1,310 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Config holds feed ranking and storage configuration
+type Config struct {
+	DefaultMix   map[string]int
+	DefaultLimit int
+	MaxLimit     int
+	Window       int           // Candidates loaded per type before ranking
+	HalfLife     time.Duration // Age at which the recency component halves
+	VideosDB     string
+	ViralDB      string
+	MemesDB      string
+}
+
+// Ranking weights per sort mode: recency, score
+var sortWeights = map[string][2]float64{
+	"blend":  {0.6, 0.4},
+	"recent": {1, 0},
+	"top":    {0, 1},
+}
+
+// IsValidSort reports whether sort is a supported ranking mode
+func IsValidSort(sort string) bool {
+	_, ok := sortWeights[sort]
+	return ok
+}
+
+// LoadConfig reads feed configuration from the environment
+func LoadConfig() *Config {
+	mix, err := ParseMix(getEnvOrDefault("FEED_MIX", "article:3,video:1,viral:1,meme:1"))
+	if err != nil {
+		log.Printf("Invalid FEED_MIX, using equal mix: %v", err)
+		mix = map[string]int{TypeArticle: 1, TypeVideo: 1, TypeViral: 1, TypeMeme: 1}
+	}
+
+	return &Config{
+		DefaultMix:   mix,
+		DefaultLimit: getEnvIntOrDefault("FEED_DEFAULT_LIMIT", 20),
+		MaxLimit:     getEnvIntOrDefault("FEED_MAX_LIMIT", 100),
+		Window:       getEnvIntOrDefault("FEED_CANDIDATE_WINDOW", 300),
+		HalfLife:     time.Duration(getEnvIntOrDefault("FEED_HALF_LIFE_HOURS", 12)) * time.Hour,
+		VideosDB:     getEnvOrDefault("FEED_VIDEOS_DB", "videosdb"),
+		ViralDB:      getEnvOrDefault("FEED_VIRAL_DB", "viraldb"),
+		MemesDB:      getEnvOrDefault("FEED_MEMES_DB", "memesdb"),
+	}
+}
+
+// Service builds mixed feed pages from all content collections
+type Service struct {
+	config  *Config
+	sources map[string]Source
+}
+
+// NewService wires a source per content type. articles is the news-service
+// collection; the rest live in the other services' databases on the same cluster.
+func NewService(articles *mongo.Collection, config *Config) *Service {
+	client := articles.Database().Client()
+
+	return &Service{
+		config: config,
+		sources: map[string]Source{
+			TypeArticle: NewArticleSource(articles),
+			TypeVideo:   NewVideoSource(client.Database(config.VideosDB).Collection("videos")),
+			TypeViral:   NewViralSource(client.Database(config.ViralDB).Collection("stories")),
+			TypeMeme:    NewMemeSource(client.Database(config.MemesDB).Collection("memes")),
+		},
+	}
+}
+
+// Config returns the service configuration
+func (s *Service) Config() *Config {
+	return s.config
+}
+
+// Page returns the next page of the feed for q
+func (s *Service) Page(ctx context.Context, q Query) (*Page, error) {
+	weights, ok := sortWeights[q.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	cursor := q.Cursor
+	if cursor == nil {
+		cursor = &Cursor{AsOf: time.Now(), Offsets: make(map[string]int)}
+	}
+
+	mix := s.effectiveMix(q)
+	if len(mix) == 0 {
+		return &Page{Items: []FeedItem{}}, nil
+	}
+
+	ranked, err := s.loadRanked(ctx, q, mix, cursor.AsOf, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remaining items per type after what earlier pages consumed
+	remaining := make(map[string][]FeedItem, len(ranked))
+	for typ, items := range ranked {
+		offset := cursor.Offsets[typ]
+		if offset < len(items) {
+			remaining[typ] = items[offset:]
+		}
+	}
+
+	items := interleave(remaining, mix, q.Limit)
+
+	next := &Cursor{AsOf: cursor.AsOf, Offsets: make(map[string]int, len(ranked))}
+	for typ := range ranked {
+		next.Offsets[typ] = cursor.Offsets[typ]
+	}
+	for _, item := range items {
+		next.Offsets[item.Type]++
+	}
+
+	hasMore := false
+	for typ, items := range ranked {
+		if next.Offsets[typ] < len(items) {
+			hasMore = true
+			break
+		}
+	}
+
+	page := &Page{Items: items, HasMore: hasMore}
+	if hasMore {
+		page.NextCursor = next.Encode()
+	}
+	return page, nil
+}
+
+// effectiveMix applies the type filter to the requested or default mix
+func (s *Service) effectiveMix(q Query) map[string]int {
+	mix := q.Mix
+	if len(mix) == 0 {
+		mix = s.config.DefaultMix
+	}
+
+	allowed := make(map[string]bool)
+	for _, typ := range q.Types {
+		allowed[typ] = true
+	}
+
+	result := make(map[string]int)
+	for typ, weight := range mix {
+		if weight <= 0 || s.sources[typ] == nil {
+			continue
+		}
+		if len(allowed) > 0 && !allowed[typ] {
+			continue
+		}
+		result[typ] = weight
+	}
+	return result
+}
+
+// loadRanked fetches candidates for every type concurrently and orders each
+// list by rank. A failing source is dropped rather than failing the feed.
+func (s *Service) loadRanked(ctx context.Context, q Query, mix map[string]int, asOf time.Time, weights [2]float64) (map[string][]FeedItem, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ranked := make(map[string][]FeedItem)
+	var errs []string
+
+	for typ := range mix {
+		wg.Add(1)
+		go func(source Source) {
+			defer wg.Done()
+
+			items, err := source.Candidates(ctx, q, asOf, s.config.Window)
+			if err != nil {
+				log.Printf("Feed source %s failed: %v", source.Type(), err)
+				mu.Lock()
+				errs = append(errs, err.Error())
+				mu.Unlock()
+				return
+			}
+
+			for i := range items {
+				items[i].Rank = s.rank(items[i], asOf, weights)
+			}
+			sort.SliceStable(items, func(i, j int) bool {
+				if items[i].Rank != items[j].Rank {
+					return items[i].Rank > items[j].Rank
+				}
+				return items[i].ID < items[j].ID
+			})
+
+			mu.Lock()
+			ranked[source.Type()] = items
+			mu.Unlock()
+		}(s.sources[typ])
+	}
+	wg.Wait()
+
+	if len(ranked) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("all feed sources failed: %s", strings.Join(errs, "; "))
+	}
+	return ranked, nil
+}
+
+// rank blends exponential recency decay with the normalised popularity score.
+// Age is measured against the cursor time so ranks are stable across pages.
+func (s *Service) rank(item FeedItem, asOf time.Time, weights [2]float64) float64 {
+	recency := 0.5 // unknown publish time
+	if !item.PublishedAt.IsZero() {
+		age := asOf.Sub(item.PublishedAt)
+		if age < 0 {
+			age = 0
+		}
+		recency = math.Exp(-math.Ln2 * age.Hours() / s.config.HalfLife.Hours())
+	}
+	return weights[0]*recency + weights[1]*item.Score
+}
+
+// interleave merges per-type lists using smooth weighted round-robin so a
+// 3:1 mix yields A A B A rather than A A A B. Exhausted types drop out.
+func interleave(lists map[string][]FeedItem, mix map[string]int, limit int) []FeedItem {
+	items := make([]FeedItem, 0, limit)
+	positions := make(map[string]int)
+	current := make(map[string]int)
+
+	for len(items) < limit {
+		best := ""
+		total := 0
+		for _, typ := range AllTypes {
+			weight := mix[typ]
+			if weight <= 0 || positions[typ] >= len(lists[typ]) {
+				continue
+			}
+			current[typ] += weight
+			total += weight
+			if best == "" || current[typ] > current[best] {
+				best = typ
+			}
+		}
+		if best == "" {
+			break
+		}
+
+		current[best] -= total
+		items = append(items, lists[best][positions[best]])
+		positions[best]++
+	}
+	return items
+}
+
+// ParseMix parses "article:3,video:1" into type weights
+func ParseMix(value string) (map[string]int, error) {
+	mix := make(map[string]int)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		typ, weightStr, ok := strings.Cut(part, ":")
+		if !ok {
+			weightStr = "1"
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight in %q", part)
+		}
+		if !isKnownType(typ) {
+			return nil, fmt.Errorf("unknown content type %q", typ)
+		}
+		mix[typ] = weight
+	}
+	return mix, nil
+}
+
+func isKnownType(typ string) bool {
+	for _, known := range AllTypes {
+		if typ == known {
+			return true
+		}
+	}
+	return false
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,313 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Source loads ranking candidates of one content type
+type Source interface {
+	Type() string
+	Candidates(ctx context.Context, q Query, asOf time.Time, window int) ([]FeedItem, error)
+}
+
+// ArticleSource reads newsdb.articles
+type ArticleSource struct {
+	collection *mongo.Collection
+}
+
+func NewArticleSource(collection *mongo.Collection) *ArticleSource {
+	return &ArticleSource{collection: collection}
+}
+
+func (s *ArticleSource) Type() string { return TypeArticle }
+
+func (s *ArticleSource) Candidates(ctx context.Context, q Query, asOf time.Time, window int) ([]FeedItem, error) {
+	filter := bson.M{"fetchedAt": bson.M{"$lte": asOf}}
+	if q.Region != "" {
+		filter["topic"] = strings.ToLower(q.Region)
+	}
+	if len(q.ExcludeSources) > 0 {
+		filter["source.name"] = bson.M{"$nin": q.ExcludeSources}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}}).
+		SetLimit(int64(window))
+
+	var docs []struct {
+		ID          primitive.ObjectID `bson:"_id"`
+		Title       string             `bson:"title"`
+		Description string             `bson:"description"`
+		URL         string             `bson:"url"`
+		Image       string             `bson:"image"`
+		Source      struct {
+			Name string `bson:"name"`
+		} `bson:"source"`
+		PublishedAt time.Time `bson:"publishedAt"`
+		Topic       string    `bson:"topic"`
+	}
+	if err := findAll(ctx, s.collection, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedItem, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, FeedItem{
+			ID:          TypeArticle + ":" + doc.ID.Hex(),
+			Type:        TypeArticle,
+			Title:       doc.Title,
+			Description: doc.Description,
+			URL:         doc.URL,
+			Image:       doc.Image,
+			Source:      doc.Source.Name,
+			Region:      doc.Topic,
+			PublishedAt: doc.PublishedAt,
+			// Articles carry no engagement data; rank them on recency alone
+			Score: 0.5,
+		})
+	}
+	return items, nil
+}
+
+// VideoSource reads videosdb.videos
+type VideoSource struct {
+	collection *mongo.Collection
+}
+
+func NewVideoSource(collection *mongo.Collection) *VideoSource {
+	return &VideoSource{collection: collection}
+}
+
+func (s *VideoSource) Type() string { return TypeVideo }
+
+func (s *VideoSource) Candidates(ctx context.Context, q Query, asOf time.Time, window int) ([]FeedItem, error) {
+	filter := bson.M{
+		"fetchedAt": bson.M{"$lte": asOf},
+		// Search results are not trending content; dead videos are awaiting purge
+		"origin":       bson.M{"$ne": "search"},
+		"availability": bson.M{"$ne": "unavailable"},
+	}
+	if q.Region != "" {
+		filter["region"] = strings.ToUpper(q.Region)
+	}
+	if len(q.Categories) > 0 {
+		filter["categoryName"] = bson.M{"$in": q.Categories}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}}).
+		SetLimit(int64(window))
+
+	var docs []struct {
+		ID           primitive.ObjectID `bson:"_id"`
+		VideoID      string             `bson:"videoId"`
+		Title        string             `bson:"title"`
+		Description  string             `bson:"description"`
+		ChannelTitle string             `bson:"channelTitle"`
+		CategoryName string             `bson:"categoryName"`
+		Region       string             `bson:"region"`
+		PublishedAt  time.Time          `bson:"publishedAt"`
+		Thumbnail    string             `bson:"thumbnail"`
+		VideoURL     string             `bson:"videoUrl"`
+		ViewCount    int64              `bson:"viewCount"`
+		LikeCount    int64              `bson:"likeCount"`
+		Duration     string             `bson:"duration"`
+	}
+	if err := findAll(ctx, s.collection, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedItem, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, FeedItem{
+			ID:          TypeVideo + ":" + doc.VideoID,
+			Type:        TypeVideo,
+			Title:       doc.Title,
+			Description: doc.Description,
+			URL:         doc.VideoURL,
+			Image:       doc.Thumbnail,
+			Source:      "youtube",
+			Region:      doc.Region,
+			Category:    doc.CategoryName,
+			PublishedAt: doc.PublishedAt,
+			// 100M views saturates the score
+			Score: logScore(float64(doc.ViewCount), 8),
+			Metadata: map[string]interface{}{
+				"videoId":      doc.VideoID,
+				"channelTitle": doc.ChannelTitle,
+				"viewCount":    doc.ViewCount,
+				"likeCount":    doc.LikeCount,
+				"duration":     doc.Duration,
+			},
+		})
+	}
+	return items, nil
+}
+
+// ViralSource reads viraldb.stories
+type ViralSource struct {
+	collection *mongo.Collection
+}
+
+func NewViralSource(collection *mongo.Collection) *ViralSource {
+	return &ViralSource{collection: collection}
+}
+
+func (s *ViralSource) Type() string { return TypeViral }
+
+func (s *ViralSource) Candidates(ctx context.Context, q Query, asOf time.Time, window int) ([]FeedItem, error) {
+	filter := bson.M{"fetched_at": bson.M{"$lte": asOf}}
+	excludeModerated(filter)
+	if len(q.Categories) > 0 {
+		filter["category"] = bson.M{"$in": q.Categories}
+	}
+	if len(q.ExcludeSources) > 0 {
+		filter["source"] = bson.M{"$nin": q.ExcludeSources}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "published_at", Value: -1}}).
+		SetLimit(int64(window))
+
+	var docs []struct {
+		ID          string    `bson:"_id"`
+		Title       string    `bson:"title"`
+		Description string    `bson:"description"`
+		URL         string    `bson:"url"`
+		ImageURL    string    `bson:"image_url"`
+		Source      string    `bson:"source"`
+		Author      string    `bson:"author"`
+		Category    string    `bson:"category"`
+		PublishedAt time.Time `bson:"published_at"`
+		ViralScore  int       `bson:"viral_score"`
+		Upvotes     int       `bson:"upvotes"`
+		Comments    int       `bson:"comments"`
+	}
+	if err := findAll(ctx, s.collection, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedItem, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, FeedItem{
+			ID:          TypeViral + ":" + doc.ID,
+			Type:        TypeViral,
+			Title:       doc.Title,
+			Description: doc.Description,
+			URL:         doc.URL,
+			Image:       doc.ImageURL,
+			Source:      doc.Source,
+			Category:    doc.Category,
+			PublishedAt: doc.PublishedAt,
+			Score:       clamp(float64(doc.ViralScore) / 100.0),
+			Metadata: map[string]interface{}{
+				"author":   doc.Author,
+				"upvotes":  doc.Upvotes,
+				"comments": doc.Comments,
+			},
+		})
+	}
+	return items, nil
+}
+
+// maxMemeHotRank is the hot_rank memes-service gives the top meme of a source
+// at age zero; see assignHotRanks there
+var maxMemeHotRank = 1.1 / math.Pow(2, 1.5)
+
+// MemeSource reads memesdb.memes
+type MemeSource struct {
+	collection *mongo.Collection
+}
+
+func NewMemeSource(collection *mongo.Collection) *MemeSource {
+	return &MemeSource{collection: collection}
+}
+
+func (s *MemeSource) Type() string { return TypeMeme }
+
+func (s *MemeSource) Candidates(ctx context.Context, q Query, asOf time.Time, window int) ([]FeedItem, error) {
+	filter := bson.M{"fetched_at": bson.M{"$lte": asOf}}
+	excludeModerated(filter)
+	if len(q.ExcludeSources) > 0 {
+		filter["source"] = bson.M{"$nin": q.ExcludeSources}
+	}
+
+	// hot_rank is comparable across sources, unlike raw scores; _id breaks ties
+	opts := options.Find().
+		SetSort(bson.D{{Key: "hot_rank", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(window))
+
+	var docs []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		Title     string             `bson:"title"`
+		ImageURL  string             `bson:"image_url"`
+		Source    string             `bson:"source"`
+		Permalink string             `bson:"permalink"`
+		Score     int                `bson:"score"`
+		CreatedAt time.Time          `bson:"created_at"`
+		HotRank   float64            `bson:"hot_rank"`
+	}
+	if err := findAll(ctx, s.collection, filter, opts, &docs); err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedItem, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, FeedItem{
+			ID:     TypeMeme + ":" + doc.ID.Hex(),
+			Type:   TypeMeme,
+			Title:  doc.Title,
+			URL:    doc.Permalink,
+			Image:  doc.ImageURL,
+			Source: doc.Source,
+			// Zero for Imgflip templates; rank treats the age as unknown
+			PublishedAt: doc.CreatedAt,
+			Score:       clamp(doc.HotRank / maxMemeHotRank),
+			Metadata: map[string]interface{}{
+				"score": doc.Score,
+			},
+		})
+	}
+	return items, nil
+}
+
+// excludeModerated drops viral stories and memes hidden by moderation or
+// flagged NSFW; the feed has no opt-in for either
+func excludeModerated(filter bson.M) {
+	filter["moderation.hidden"] = bson.M{"$ne": true}
+	filter["nsfw"] = bson.M{"$ne": true}
+}
+
+func findAll(ctx context.Context, collection *mongo.Collection, filter bson.M, opts *options.FindOptions, results interface{}) error {
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("%s query failed: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, results); err != nil {
+		return fmt.Errorf("%s decode failed: %w", collection.Name(), err)
+	}
+	return nil
+}
+
+// logScore maps a count onto 0..1 where 10^maxExp saturates
+func logScore(count float64, maxExp float64) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return clamp(math.Log10(count+1) / maxExp)
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	"log"
+	"news-service/entity"
 	"news-service/model"
 	"sync"
 	"time"
@@ -302,22 +303,16 @@ func generateDemographics(region string) map[string]int64 {
 }
 
 func extractTags(article *model.Article) []string {
-	// Simple keyword extraction from title
-	// In production, you'd use NLP libraries
+	// Tag with named entities so trending works per person, organisation
+	// and place rather than per word
 	keywords := []string{}
-
-	// Common news keywords
-	commonWords := map[string]bool{
-		"the": true, "and": true, "or": true, "but": true, "in": true,
-		"on": true, "at": true, "to": true, "for": true, "of": true,
-		"with": true, "by": true, "a": true, "an": true, "is": true,
-		"are": true, "was": true, "were": true, "be": true, "been": true,
-	}
-
-	words := []string{"technology", "politics", "business", "sports", "health"}
-	for _, word := range words {
-		if len(word) > 3 && !commonWords[word] {
-			keywords = append(keywords, word)
+	if len(article.Entities) > 0 {
+		for _, e := range article.Entities {
+			keywords = append(keywords, e.Name)
+		}
+	} else {
+		for _, m := range entity.ForArticle(article.Title, article.Description, article.Lang, article.Topic) {
+			keywords = append(keywords, m.Name)
 		}
 	}
 
@@ -24,6 +24,8 @@ type NewsHandler struct {
 	natsPublisher      *NATSPublisher
 	streamingService   *NATSStreamingService
 	analyticsProcessor *AnalyticsProcessor
+	outbox             *OutboxRelay
+	stopOutbox         context.CancelFunc
 }
 
 // Configuration struct for news fetching
@@ -40,6 +42,8 @@ type NewsConfig struct {
 	RegionStrategies map[string]string
 	EnableJetStream  bool
 	StreamingConfig  *StreamingConfig
+	EnableOutbox     bool
+	OutboxConfig     *OutboxConfig
 }
 
 func NewNewsHandler(collection *mongo.Collection) *NewsHandler {
@@ -72,6 +76,22 @@ func NewNewsHandler(collection *mongo.Collection) *NewsHandler {
 		}
 	}
 
+	// Initialize the outbox relay; article events then go through Mongo first.
+	// Its writes need transactions, so a standalone server publishes directly.
+	var outbox *OutboxRelay
+	stopOutbox := func() {}
+	if config.EnableOutbox && streamingService != nil {
+		if supportsTransactions(collection.Database().Client()) {
+			outboxCollection := collection.Database().Collection(config.OutboxConfig.Collection)
+			outbox = NewOutboxRelay(outboxCollection, streamingService, config.OutboxConfig)
+			var ctx context.Context
+			ctx, stopOutbox = context.WithCancel(context.Background())
+			go outbox.Start(ctx)
+		} else {
+			log.Println("Warning: MongoDB is not a replica set or sharded cluster - outbox disabled, publishing to JetStream directly")
+		}
+	}
+
 	// Initialize strategies
 	strategies := make(map[string]NewsStrategy)
 	strategies["api"] = &APIStrategy{}
@@ -84,6 +104,17 @@ func NewNewsHandler(collection *mongo.Collection) *NewsHandler {
 		natsPublisher:      natsPublisher,
 		streamingService:   streamingService,
 		analyticsProcessor: analyticsProcessor,
+		outbox:             outbox,
+		stopOutbox:         stopOutbox,
+	}
+}
+
+// Close stops the outbox relay and closes the JetStream connection. Records
+// the relay had claimed are picked up again once their lease expires.
+func (nh *NewsHandler) Close() {
+	nh.stopOutbox()
+	if nh.streamingService != nil {
+		nh.streamingService.Close()
 	}
 }
 
@@ -91,6 +122,7 @@ func NewNewsHandler(collection *mongo.Collection) *NewsHandler {
 func loadImprovedNewsConfig() *NewsConfig {
 	enableNATS, _ := strconv.ParseBool(getEnvOrDefault("ENABLE_NATS", "false"))
 	enableJetStream, _ := strconv.ParseBool(getEnvOrDefault("ENABLE_JETSTREAM", "true"))
+	enableOutbox, _ := strconv.ParseBool(getEnvOrDefault("ENABLE_OUTBOX", "true"))
 
 	var natsConfig *NATSConfig
 	if enableNATS {
@@ -113,6 +145,18 @@ func loadImprovedNewsConfig() *NewsConfig {
 		}
 	}
 
+	var outboxConfig *OutboxConfig
+	if enableOutbox {
+		outboxConfig = &OutboxConfig{
+			Collection:   getEnvOrDefault("OUTBOX_COLLECTION", "outbox"),
+			BatchSize:    getEnvIntOrDefault("OUTBOX_BATCH_SIZE", 100),
+			PollInterval: time.Duration(getEnvIntOrDefault("OUTBOX_POLL_INTERVAL_SECONDS", 5)) * time.Second,
+			LeaseTime:    30 * time.Second,
+			MaxAttempts:  getEnvIntOrDefault("OUTBOX_MAX_ATTEMPTS", 10),
+			RetainSent:   7 * 24 * time.Hour,
+		}
+	}
+
 	// Region-specific strategies: "in" and "us" use RSS, others use API
 	regionStrategies := make(map[string]string)
 	regions := strings.Split(getEnvOrDefault("NEWS_REGIONS", "us,in,de"), ",")
@@ -137,14 +181,16 @@ func loadImprovedNewsConfig() *NewsConfig {
 		RegionStrategies: regionStrategies,
 		EnableJetStream:  enableJetStream,
 		StreamingConfig:  streamingConfig,
+		EnableOutbox:     enableOutbox,
+		OutboxConfig:     outboxConfig,
 	}
 
 	if config.APIKey == "" {
 		log.Println("Warning: Missing NEWS_API_KEY environment variable - API strategy will not work")
 	}
 
-	log.Printf("Hybrid News Config: BaseURL=%s, Regions=%v, MaxPages=%d, MaxArticles=%d, NATS=%t, JetStream=%t",
-		config.BaseURL, config.Regions, config.MaxPages, config.MaxArticles, config.EnableNATS, config.EnableJetStream)
+	log.Printf("Hybrid News Config: BaseURL=%s, Regions=%v, MaxPages=%d, MaxArticles=%d, NATS=%t, JetStream=%t, Outbox=%t",
+		config.BaseURL, config.Regions, config.MaxPages, config.MaxArticles, config.EnableNATS, config.EnableJetStream, config.EnableOutbox)
 
 	return config
 }
@@ -226,13 +272,7 @@ func (nh *NewsHandler) FetchNews(c *gin.Context) {
 	}
 
 	// Publish to JetStream if enabled
-	if nh.streamingService != nil {
-		for _, article := range articles {
-			if err := nh.streamingService.PublishArticle(article, "article_published"); err != nil {
-				log.Printf("Failed to publish article to JetStream: %v", err)
-			}
-		}
-	}
+	nh.publishToJetStream(articles)
 
 	// Record metrics
 	if nh.analyticsProcessor != nil {
@@ -250,6 +290,10 @@ func (nh *NewsHandler) FetchNews(c *gin.Context) {
 }
 
 func (nh *NewsHandler) storeArticles(articles []model.Article) int {
+	if nh.outbox != nil {
+		return nh.storeArticlesWithOutbox(articles)
+	}
+
 	stored := 0
 	for _, article := range articles {
 		filter := bson.M{"url": article.URL}
@@ -273,6 +317,69 @@ func (nh *NewsHandler) storeArticles(articles []model.Article) int {
 	return stored
 }
 
+// storeArticlesWithOutbox upserts each article and records its event in the
+// outbox within one transaction, so an event exists if and only if the write does
+func (nh *NewsHandler) storeArticlesWithOutbox(articles []model.Article) int {
+	ctx := context.TODO()
+
+	session, err := nh.collection.Database().Client().StartSession()
+	if err != nil {
+		log.Printf("Failed to start MongoDB session: %v", err)
+		return 0
+	}
+	defer session.EndSession(ctx)
+
+	stored := 0
+	for _, article := range articles {
+		eventType, err := session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			result, err := nh.collection.UpdateOne(
+				sc,
+				bson.M{"url": article.URL},
+				bson.M{"$set": article},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			eventType := ""
+			if result.UpsertedCount > 0 {
+				eventType = "article_published"
+			} else if result.ModifiedCount > 0 {
+				eventType = "article_updated"
+			}
+			if eventType == "" {
+				return eventType, nil
+			}
+
+			return eventType, nh.outbox.Enqueue(sc, article, eventType)
+		})
+
+		if err != nil {
+			log.Printf("Insert failed for article: %s | error: %v", article.URL, err)
+			continue
+		}
+		if eventType != "" {
+			stored++
+		}
+	}
+	return stored
+}
+
+// publishToJetStream publishes article events directly when the outbox is
+// disabled; with the outbox enabled the relay publishes them instead
+func (nh *NewsHandler) publishToJetStream(articles []model.Article) {
+	if nh.streamingService == nil || nh.outbox != nil {
+		return
+	}
+
+	for _, article := range articles {
+		if err := nh.streamingService.PublishArticle(article, "article_published"); err != nil {
+			log.Printf("Failed to publish article to JetStream for region %s: %v", article.Topic, err)
+		}
+	}
+}
+
 // TriggerNewsFetch manually triggers news fetching for a specific region
 func (nh *NewsHandler) TriggerNewsFetch(region, priority string) error {
 	strategy := nh.config.RegionStrategies[region]
@@ -303,13 +410,7 @@ func (nh *NewsHandler) TriggerNewsFetch(region, priority string) error {
 	}
 
 	// Publish to JetStream if enabled
-	if nh.streamingService != nil && len(articles) > 0 {
-		for _, article := range articles {
-			if err := nh.streamingService.PublishArticle(article, "article_published"); err != nil {
-				log.Printf("Failed to publish article to JetStream: %v", err)
-			}
-		}
-	}
+	nh.publishToJetStream(articles)
 
 	return nil
 }
@@ -330,9 +431,10 @@ func (nh *NewsHandler) TriggerAllRegionsFetch(priority string) error {
 	return nil
 }
 
-// StartScheduledFetcher runs the hybrid news fetcher
-func StartScheduledFetcher(db *mongo.Database) {
+// StartScheduledFetcher runs the hybrid news fetcher until ctx is cancelled
+func StartScheduledFetcher(ctx context.Context, db *mongo.Database) {
 	handler := NewNewsHandler(db.Collection("articles"))
+	defer handler.Close()
 	config := handler.config
 
 	log.Println("Starting hybrid scheduled news fetcher...")
@@ -341,9 +443,15 @@ func StartScheduledFetcher(db *mongo.Database) {
 	handler.fetchAndStoreAllRegions()
 
 	ticker := time.NewTicker(config.FetchInterval)
+	defer ticker.Stop()
 	for {
-		<-ticker.C
-		handler.fetchAndStoreAllRegions()
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduled news fetcher stopped")
+			return
+		case <-ticker.C:
+			handler.fetchAndStoreAllRegions()
+		}
 	}
 }
 
@@ -381,13 +489,7 @@ func (nh *NewsHandler) fetchAndStoreAllRegions() {
 		}
 
 		// Publish to JetStream if enabled
-		if nh.streamingService != nil && len(articles) > 0 {
-			for _, article := range articles {
-				if err := nh.streamingService.PublishArticle(article, "article_published"); err != nil {
-					log.Printf("Failed to publish article to JetStream for region %s: %v", region, err)
-				}
-			}
-		}
+		nh.publishToJetStream(articles)
 
 		// Rate limiting between regions
 		time.Sleep(nh.config.RateLimit)
@@ -471,13 +573,7 @@ func (nh *NewsHandler) CleanupAndRefreshRegion(region string) (map[string]interf
 	}
 	
 	// Step 6: Publish to JetStream if enabled
-	if nh.streamingService != nil && len(articles) > 0 {
-		for _, article := range articles {
-			if err := nh.streamingService.PublishArticle(article, "article_published"); err != nil {
-				log.Printf("Failed to publish article to JetStream: %v", err)
-			}
-		}
-	}
+	nh.publishToJetStream(articles)
 	
 	result := map[string]interface{}{
 		"region":              region,
@@ -505,6 +601,10 @@ func (nh *NewsHandler) GetAnalyticsProcessor() *AnalyticsProcessor {
 	return nh.analyticsProcessor
 }
 
+func (nh *NewsHandler) GetOutbox() *OutboxRelay {
+	return nh.outbox
+}
+
 func (nh *NewsHandler) GetConfig() *NewsConfig {
 	return nh.config
 }
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"news-service/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Outbox record states
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxConfig holds configuration for the transactional outbox relay
+type OutboxConfig struct {
+	Collection   string
+	BatchSize    int
+	PollInterval time.Duration
+	LeaseTime    time.Duration
+	MaxAttempts  int
+	RetainSent   time.Duration
+}
+
+// OutboxRecord is an event written in the same transaction as the article it describes
+type OutboxRecord struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Subject     string             `bson:"subject" json:"subject"`
+	EventType   string             `bson:"eventType" json:"eventType"`
+	ArticleURL  string             `bson:"articleUrl" json:"articleUrl"`
+	Payload     []byte             `bson:"payload" json:"-"`
+	Status      string             `bson:"status" json:"status"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	LastError   string             `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	LockedUntil time.Time          `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	SentAt      *time.Time         `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+}
+
+// MsgID returns the JetStream de-duplication ID for the record
+func (r *OutboxRecord) MsgID() string {
+	return r.ID.Hex()
+}
+
+// OutboxRelay publishes pending outbox records to JetStream and marks them sent
+type OutboxRelay struct {
+	collection *mongo.Collection
+	streaming  *NATSStreamingService
+	config     *OutboxConfig
+}
+
+// NewOutboxRelay creates a relay for the given outbox collection
+func NewOutboxRelay(collection *mongo.Collection, streaming *NATSStreamingService, config *OutboxConfig) *OutboxRelay {
+	relay := &OutboxRelay{
+		collection: collection,
+		streaming:  streaming,
+		config:     config,
+	}
+
+	relay.ensureIndexes()
+	return relay
+}
+
+// supportsTransactions reports whether the deployment behind client can run
+// multi-document transactions, which the outbox writes depend on. Standalone
+// servers cannot; replica set members report a setName and mongos "isdbgrid".
+func supportsTransactions(client *mongo.Client) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		log.Printf("Failed to check MongoDB topology: %v", err)
+		return false
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid"
+}
+
+func (r *OutboxRelay) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+		},
+		{
+			// Sent records are only kept for inspection; pending ones never expire
+			Keys:    bson.D{{Key: "sentAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(r.config.RetainSent.Seconds())),
+		},
+	}
+
+	if _, err := r.collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("Warning: Failed to create outbox indexes: %v", err)
+	}
+}
+
+// Enqueue inserts an outbox record for an article event. Pass the session context
+// of the transaction that writes the article so both commit or neither does.
+func (r *OutboxRelay) Enqueue(ctx context.Context, article model.Article, eventType string) error {
+	id := primitive.NewObjectID()
+
+	event := NewsEvent{
+		ID:        id.Hex(),
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Source:    "news-service",
+		Region:    article.Topic,
+		Data: EventData{
+			Article: &article,
+		},
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+
+	record := OutboxRecord{
+		ID:         id,
+		Subject:    fmt.Sprintf("news.articles.%s", article.Topic),
+		EventType:  eventType,
+		ArticleURL: article.URL,
+		Payload:    payload,
+		Status:     OutboxStatusPending,
+		CreatedAt:  event.Timestamp,
+	}
+
+	_, err = r.collection.InsertOne(ctx, record)
+	return err
+}
+
+// Start runs the relay loop until the context is cancelled
+func (r *OutboxRelay) Start(ctx context.Context) {
+	log.Printf("Outbox relay started: collection=%s, batch=%d, interval=%v",
+		r.collection.Name(), r.config.BatchSize, r.config.PollInterval)
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			sent, err := r.RelayBatch(ctx)
+			if err != nil {
+				log.Printf("Outbox relay error: %v", err)
+			} else if sent > 0 {
+				log.Printf("Outbox relay published %d events", sent)
+			}
+		}
+	}
+}
+
+// RelayBatch claims up to BatchSize pending records and publishes them
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	sent := 0
+	for i := 0; i < r.config.BatchSize; i++ {
+		record, err := r.claimNext(ctx)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return sent, fmt.Errorf("failed to claim outbox record: %w", err)
+		}
+
+		if err := r.streaming.PublishWithMsgID(record.Subject, record.Payload, record.MsgID()); err != nil {
+			r.markFailed(ctx, record, err)
+			continue
+		}
+
+		if err := r.markSent(ctx, record); err != nil {
+			// The event is out; a later retry is de-duplicated by JetStream via Nats-Msg-Id
+			log.Printf("Failed to mark outbox record %s as sent: %v", record.MsgID(), err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// claimNext leases the oldest pending record so concurrent relays don't publish it twice
+func (r *OutboxRelay) claimNext(ctx context.Context) (*OutboxRecord, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status": OutboxStatusPending,
+		"$or": []bson.M{
+			{"lockedUntil": bson.M{"$exists": false}},
+			{"lockedUntil": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"lockedUntil": now.Add(r.config.LeaseTime)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var record OutboxRecord
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *OutboxRelay) markSent(ctx context.Context, record *OutboxRecord) error {
+	now := time.Now()
+	_, err := r.collection.UpdateByID(ctx, record.ID, bson.M{
+		"$set":   bson.M{"status": OutboxStatusSent, "sentAt": now},
+		"$unset": bson.M{"lockedUntil": "", "lastError": ""},
+	})
+	return err
+}
+
+func (r *OutboxRelay) markFailed(ctx context.Context, record *OutboxRecord, publishErr error) {
+	log.Printf("Failed to relay outbox record %s (attempt %d): %v", record.MsgID(), record.Attempts, publishErr)
+
+	set := bson.M{"lastError": publishErr.Error()}
+	if record.Attempts >= r.config.MaxAttempts {
+		set["status"] = OutboxStatusFailed
+	}
+
+	// Release the lease so the record is retried on the next tick
+	if _, err := r.collection.UpdateByID(ctx, record.ID, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"lockedUntil": ""},
+	}); err != nil {
+		log.Printf("Failed to update outbox record %s: %v", record.MsgID(), err)
+	}
+}
+
+// Stats returns the number of outbox records per status
+func (r *OutboxRelay) Stats(ctx context.Context) (map[string]int64, error) {
+	stats := map[string]int64{}
+	for _, status := range []string{OutboxStatusPending, OutboxStatusSent, OutboxStatusFailed} {
+		count, err := r.collection.CountDocuments(ctx, bson.M{"status": status})
+		if err != nil {
+			return nil, err
+		}
+		stats[status] = count
+	}
+	return stats, nil
+}
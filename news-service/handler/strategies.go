@@ -7,6 +7,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"news-service/classify"
+	"news-service/langdetect"
 	"news-service/model"
 	"strings"
 	"time"
@@ -162,6 +164,9 @@ func (r *RSSStrategy) fetchFromRSSSource(sourceURL, region string) ([]model.Arti
 			Topic:       region,
 			FetchedAt:   time.Now(),
 		}
+		article.Lang = langdetect.ForArticle(article.Title, article.Description, region)
+		category := classify.ForArticle(article.Title, article.Description)
+		article.Category, article.CategoryConfidence = category.Category, category.Confidence
 
 		// Skip empty articles
 		if article.Title != "" && article.URL != "" {
@@ -238,7 +243,8 @@ func extractImageFromHTML(html string) string {
 	return ""
 }
 
-func getDefaultNewsImage(region string) string {
+// DefaultNewsImage returns the placeholder image for a region
+func DefaultNewsImage(region string) string {
 	// Default news images by region
 	defaultImages := map[string]string{
 		"in": "https://images.unsplash.com/photo-1504711434969-e33886168f5c?w=400&h=200&fit=crop&crop=entropy&auto=format&q=80",
@@ -260,12 +266,12 @@ func getDefaultNewsImage(region string) string {
 func validateAndFixImageURL(imageURL, region string) string {
 	// If no image URL provided, use default
 	if imageURL == "" {
-		return getDefaultNewsImage(region)
+		return DefaultNewsImage(region)
 	}
 
 	// Check if it's a valid HTTP/HTTPS URL
 	if !strings.HasPrefix(imageURL, "http://") && !strings.HasPrefix(imageURL, "https://") {
-		return getDefaultNewsImage(region)
+		return DefaultNewsImage(region)
 	}
 
 	// Check for common invalid patterns
@@ -273,7 +279,7 @@ func validateAndFixImageURL(imageURL, region string) string {
 	   strings.Contains(imageURL, "undefined") || 
 	   strings.HasSuffix(imageURL, ".svg") ||
 	   len(imageURL) < 10 {
-		return getDefaultNewsImage(region)
+		return DefaultNewsImage(region)
 	}
 
 	return imageURL
@@ -334,6 +340,9 @@ func fetchRegionNewsAPI(region string, config *NewsConfig) ([]model.Article, err
 			article.Image = validateAndFixImageURL(article.Image, region)
 			
 			article.Topic = region
+			article.Lang = langdetect.ForArticle(article.Title, article.Description, region)
+			category := classify.ForArticle(article.Title, article.Description)
+			article.Category, article.CategoryConfidence = category.Category, category.Confidence
 			article.FetchedAt = time.Now()
 			allArticles = append(allArticles, article)
 		}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"news-service/model"
+	"slices"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -49,7 +50,8 @@ type StreamInfo struct {
 
 // NewsEvent represents different types of news events
 type NewsEvent struct {
-	Type      string    `json:"type"` // "article_published", "article_updated", "trending_topic", "analytics"
+	ID        string    `json:"id,omitempty"` // Outbox record ID, also sent as Nats-Msg-Id
+	Type      string    `json:"type"`         // "article_published", "article_updated", "trending_topic", "analytics"
 	Timestamp time.Time `json:"timestamp"`
 	Source    string    `json:"source"`
 	Region    string    `json:"region"`
@@ -138,14 +140,15 @@ func NewNATSStreamingService(config *StreamingConfig) (*NATSStreamingService, er
 func (nss *NATSStreamingService) initializeStreams() error {
 	// News articles stream
 	newsStream := &nats.StreamConfig{
-		Name:      "NEWS_ARTICLES",
-		Subjects:  []string{"news.articles.*", "news.updates.*"},
-		Retention: nats.LimitsPolicy,
-		MaxAge:    24 * time.Hour,
-		MaxBytes:  100 * 1024 * 1024, // 100MB
-		MaxMsgs:   10000,
-		Replicas:  1,
-		Storage:   nats.FileStorage,
+		Name:       "NEWS_ARTICLES",
+		Subjects:   []string{"news.articles.*", "news.updates.*"},
+		Retention:  nats.LimitsPolicy,
+		MaxAge:     24 * time.Hour,
+		MaxBytes:   100 * 1024 * 1024, // 100MB
+		MaxMsgs:    10000,
+		Replicas:   1,
+		Storage:    nats.FileStorage,
+		Duplicates: 10 * time.Minute, // Covers outbox relay retries after a lease expires
 	}
 
 	if err := nss.createStream(newsStream); err != nil {
@@ -194,8 +197,14 @@ func (nss *NATSStreamingService) createStream(config *nats.StreamConfig) error {
 			return fmt.Errorf("failed to create stream %s: %w", config.Name, err)
 		}
 		log.Printf("Created stream: %s", config.Name)
+	} else if streamConfigChanged(stream.Config, *config) {
+		// Streams created by older versions keep their settings until updated,
+		// e.g. the 2 minute default dedup window the outbox can't rely on
+		if _, err := nss.js.UpdateStream(config); err != nil {
+			return fmt.Errorf("failed to update stream %s: %w", config.Name, err)
+		}
+		log.Printf("Updated stream: %s", config.Name)
 	} else {
-		// Stream exists, update if needed
 		log.Printf("Stream %s already exists with %d messages", config.Name, stream.State.Msgs)
 	}
 
@@ -208,6 +217,20 @@ func (nss *NATSStreamingService) createStream(config *nats.StreamConfig) error {
 	return nil
 }
 
+// streamConfigChanged reports whether the settings we manage differ. The
+// server fills in defaults for the rest, so whole configs never compare equal.
+// Retention and storage can't be changed in place and aren't compared.
+func streamConfigChanged(current, desired nats.StreamConfig) bool {
+	if !slices.Equal(current.Subjects, desired.Subjects) {
+		return true
+	}
+	if current.MaxAge != desired.MaxAge || current.MaxBytes != desired.MaxBytes || current.MaxMsgs != desired.MaxMsgs {
+		return true
+	}
+	// Zero leaves the server default in place
+	return desired.Duplicates != 0 && current.Duplicates != desired.Duplicates
+}
+
 // PublishArticle publishes an article event
 func (nss *NATSStreamingService) PublishArticle(article model.Article, eventType string) error {
 	event := NewsEvent{
@@ -287,6 +310,20 @@ func (nss *NATSStreamingService) publishEvent(subject string, event NewsEvent) e
 	return nil
 }
 
+// PublishWithMsgID publishes a pre-encoded event with a Nats-Msg-Id header so
+// JetStream drops duplicates of the same message within the stream's window
+func (nss *NATSStreamingService) PublishWithMsgID(subject string, data []byte, msgID string) error {
+	ack, err := nss.js.Publish(subject, data, nats.MsgId(msgID))
+	if err != nil {
+		return fmt.Errorf("failed to publish to subject %s: %w", subject, err)
+	}
+
+	if ack.Duplicate {
+		log.Printf("Duplicate event dropped by JetStream: subject=%s, msgID=%s", subject, msgID)
+	}
+	return nil
+}
+
 // SubscribeToArticles subscribes to article events with a consumer
 func (nss *NATSStreamingService) SubscribeToArticles(region string, handler func(NewsEvent) error) error {
 	subject := fmt.Sprintf("news.articles.%s", region)
@@ -0,0 +1,192 @@
+package imageproxy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Image is an encoded rendition ready to serve
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+	CreatedAt   time.Time
+}
+
+// cacheKey identifies a rendition of an upstream URL
+func cacheKey(imageURL string, width int) string {
+	sum := sha256.Sum256([]byte(imageURL + "\x00" + strconv.Itoa(width)))
+	return hex.EncodeToString(sum[:])
+}
+
+type cacheEntry struct {
+	key   string
+	image *Image
+}
+
+// memoryCache is a size-bounded LRU
+type memoryCache struct {
+	mu       sync.Mutex
+	capacity int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+func newMemoryCache(capacity int64) *memoryCache {
+	return &memoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (m *memoryCache) get(key string, ttl time.Duration) (*Image, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Since(entry.image.CreatedAt) > ttl {
+		m.remove(el)
+		return nil, false
+	}
+	m.order.MoveToFront(el)
+	return entry.image, true
+}
+
+func (m *memoryCache) put(key string, img *Image) {
+	size := int64(len(img.Data))
+	if size > m.capacity {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+	m.entries[key] = m.order.PushFront(&cacheEntry{key: key, image: img})
+	m.size += size
+
+	for m.size > m.capacity {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *memoryCache) remove(el *list.Element) {
+	entry := el.Value.(*cacheEntry)
+	m.order.Remove(el)
+	delete(m.entries, entry.key)
+	m.size -= int64(len(entry.image.Data))
+}
+
+func (m *memoryCache) stats() (int, int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries), m.size
+}
+
+// diskCache stores renditions as files named by key, sharded by prefix.
+// Expiry uses the file's modification time.
+type diskCache struct {
+	dir string
+}
+
+func newDiskCache(dir string) *diskCache {
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("Warning: Failed to create image cache dir %s, disk cache disabled: %v", dir, err)
+		return nil
+	}
+	return &diskCache{dir: dir}
+}
+
+func (d *diskCache) path(key, contentType string) string {
+	ext := ".jpg"
+	if contentType == "image/png" {
+		ext = ".png"
+	}
+	return filepath.Join(d.dir, key[:2], key+ext)
+}
+
+func (d *diskCache) get(key string, ttl time.Duration) (*Image, bool) {
+	if d == nil {
+		return nil, false
+	}
+	for _, contentType := range []string{"image/jpeg", "image/png"} {
+		path := d.path(key, contentType)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) > ttl {
+			os.Remove(path)
+			return nil, false
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false
+		}
+		return &Image{Data: data, ContentType: contentType, ETag: etag(key), CreatedAt: info.ModTime()}, true
+	}
+	return nil, false
+}
+
+func (d *diskCache) put(key string, img *Image) {
+	if d == nil {
+		return
+	}
+	path := d.path(key, img.ContentType)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Printf("Failed to create image cache shard: %v", err)
+		return
+	}
+
+	// Write then rename so readers never see a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, img.Data, 0o644); err != nil {
+		log.Printf("Failed to write cached image: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		log.Printf("Failed to store cached image: %v", err)
+		os.Remove(tmp)
+	}
+}
+
+// prune deletes expired files; run periodically
+func (d *diskCache) prune(ttl time.Duration) (removed int) {
+	if d == nil {
+		return 0
+	}
+	filepath.Walk(d.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if time.Since(info.ModTime()) > ttl {
+			if os.Remove(path) == nil {
+				removed++
+			}
+		}
+		return nil
+	})
+	return removed
+}
+
+func etag(key string) string {
+	return `"` + key[:32] + `"`
+}
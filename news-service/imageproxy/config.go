@@ -0,0 +1,94 @@
+// Package imageproxy fetches article images, checks and resizes them and
+// serves them from a memory and disk cache behind signed URLs.
+package imageproxy
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config controls the image proxy
+type Config struct {
+	Secret         string        // HMAC key for signed URLs; the proxy is disabled without it
+	BasePath       string        // Path the proxy endpoint is mounted on
+	CacheDir       string        // Disk cache location; empty disables the disk cache
+	CacheTTL       time.Duration // How long cached renditions are served
+	MemoryBytes    int64         // In-memory LRU budget
+	MaxSourceBytes int64         // Largest upstream body read
+	MaxPixels      int           // Largest decoded image accepted
+	Widths         []int         // Allowed output widths, ascending
+	DefaultWidth   int
+	ThumbnailWidth int
+	Quality        int // JPEG quality
+	Timeout        time.Duration
+}
+
+// LoadConfig reads image proxy configuration from the environment
+func LoadConfig() *Config {
+	return &Config{
+		Secret:         os.Getenv("IMAGE_PROXY_SECRET"),
+		BasePath:       getEnvOrDefault("IMAGE_PROXY_PATH", "/news-api/image"),
+		CacheDir:       getEnvOrDefault("IMAGE_PROXY_CACHE_DIR", "/var/cache/images"),
+		CacheTTL:       time.Duration(getEnvIntOrDefault("IMAGE_PROXY_CACHE_TTL_HOURS", 24)) * time.Hour,
+		MemoryBytes:    int64(getEnvIntOrDefault("IMAGE_PROXY_MEMORY_MB", 32)) << 20,
+		MaxSourceBytes: int64(getEnvIntOrDefault("IMAGE_PROXY_MAX_SOURCE_MB", 8)) << 20,
+		MaxPixels:      getEnvIntOrDefault("IMAGE_PROXY_MAX_MEGAPIXELS", 12) * 1000 * 1000,
+		Widths:         parseWidths(getEnvOrDefault("IMAGE_PROXY_WIDTHS", "160,320,480,640,960,1280")),
+		DefaultWidth:   getEnvIntOrDefault("IMAGE_PROXY_DEFAULT_WIDTH", 640),
+		ThumbnailWidth: getEnvIntOrDefault("IMAGE_PROXY_THUMBNAIL_WIDTH", 320),
+		Quality:        getEnvIntOrDefault("IMAGE_PROXY_JPEG_QUALITY", 80),
+		Timeout:        time.Duration(getEnvIntOrDefault("IMAGE_PROXY_TIMEOUT_SECONDS", 10)) * time.Second,
+	}
+}
+
+// Enabled reports whether signed URLs can be issued
+func (c *Config) Enabled() bool {
+	return c.Secret != ""
+}
+
+// SnapWidth rounds a requested width up to the nearest allowed width so the
+// cache only ever holds a handful of renditions per image
+func (c *Config) SnapWidth(width int) int {
+	if width <= 0 {
+		return c.DefaultWidth
+	}
+	for _, w := range c.Widths {
+		if w >= width {
+			return w
+		}
+	}
+	return c.Widths[len(c.Widths)-1]
+}
+
+func parseWidths(value string) []int {
+	var widths []int
+	for _, part := range strings.Split(value, ",") {
+		if w, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && w > 0 {
+			widths = append(widths, w)
+		}
+	}
+	if len(widths) == 0 {
+		widths = []int{640}
+	}
+	sort.Ints(widths)
+	return widths
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,252 @@
+package imageproxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // GIFs are served as their first frame
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInvalidURL is returned for URLs the proxy refuses to fetch
+	ErrInvalidURL = errors.New("invalid image URL")
+	// ErrNotImage is returned when the upstream body isn't a supported image
+	ErrNotImage = errors.New("upstream is not a supported image")
+	// ErrTooLarge is returned when the upstream image exceeds the size limits
+	ErrTooLarge = errors.New("upstream image too large")
+)
+
+// Proxy fetches and caches image renditions
+type Proxy struct {
+	config *Config
+	client *http.Client
+	memory *memoryCache
+	disk   *diskCache
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+// call lets concurrent requests for the same rendition share one fetch
+type call struct {
+	done  chan struct{}
+	image *Image
+	err   error
+}
+
+// New creates a proxy. The HTTP client refuses to connect to private and
+// loopback addresses, since article image URLs come from third-party feeds.
+func New(config *Config) *Proxy {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+				ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+				return fmt.Errorf("refusing to connect to %s", host)
+			}
+			return nil
+		},
+	}
+
+	return &Proxy{
+		config: config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 3 {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+		memory:   newMemoryCache(config.MemoryBytes),
+		disk:     newDiskCache(config.CacheDir),
+		inflight: make(map[string]*call),
+	}
+}
+
+// Config returns the proxy configuration
+func (p *Proxy) Config() *Config {
+	return p.config
+}
+
+// Start prunes expired renditions from the disk cache
+func (p *Proxy) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if removed := p.disk.prune(p.config.CacheTTL); removed > 0 {
+				log.Printf("Pruned %d expired images from cache", removed)
+			}
+		}
+	}
+}
+
+// Get returns imageURL resized to width, from cache when possible
+func (p *Proxy) Get(ctx context.Context, imageURL string, width int) (*Image, error) {
+	width = p.config.SnapWidth(width)
+	key := cacheKey(imageURL, width)
+
+	if img, ok := p.memory.get(key, p.config.CacheTTL); ok {
+		return img, nil
+	}
+	if img, ok := p.disk.get(key, p.config.CacheTTL); ok {
+		p.memory.put(key, img)
+		return img, nil
+	}
+
+	p.mu.Lock()
+	if c, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.image, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	p.inflight[key] = c
+	p.mu.Unlock()
+
+	// Detach from the request so a client disconnect doesn't fail the waiters
+	fetchCtx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+	c.image, c.err = p.render(fetchCtx, imageURL, width, key)
+	cancel()
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	p.mu.Unlock()
+	close(c.done)
+
+	if c.err == nil {
+		p.memory.put(key, c.image)
+		p.disk.put(key, c.image)
+	}
+	return c.image, c.err
+}
+
+// Stats reports cache occupancy
+func (p *Proxy) Stats() map[string]interface{} {
+	entries, size := p.memory.stats()
+	return map[string]interface{}{
+		"enabled":       p.config.Enabled(),
+		"memoryEntries": entries,
+		"memoryBytes":   size,
+		"diskCache":     p.disk != nil,
+	}
+}
+
+func (p *Proxy) render(ctx context.Context, imageURL string, width int, key string) (*Image, error) {
+	src, err := p.fetch(ctx, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	img := resize(src, width)
+
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	if opaque(img) {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.config.Quality})
+	} else {
+		// Keep transparency; logos on white and dark themes depend on it
+		contentType = "image/png"
+		err = (&png.Encoder{CompressionLevel: png.BestSpeed}).Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return &Image{
+		Data:        buf.Bytes(),
+		ContentType: contentType,
+		ETag:        etag(key),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func (p *Proxy) fetch(ctx context.Context, imageURL string) (image.Image, error) {
+	parsed, err := url.Parse(imageURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrInvalidURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+	req.Header.Set("Accept", "image/jpeg,image/png,image/gif;q=0.8")
+	req.Header.Set("User-Agent", "ScrollFeed-ImageProxy/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream returned %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "image/") &&
+		!strings.HasPrefix(ct, "application/octet-stream") {
+		return nil, ErrNotImage
+	}
+	if resp.ContentLength > p.config.MaxSourceBytes {
+		return nil, ErrTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, p.config.MaxSourceBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(body)) > p.config.MaxSourceBytes {
+		return nil, ErrTooLarge
+	}
+
+	// Trust the bytes rather than the header, and only formats the stdlib decodes
+	switch http.DetectContentType(body) {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrNotImage
+	}
+
+	// Check dimensions before decoding so a tiny file can't expand into gigabytes
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, ErrNotImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > p.config.MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, ErrNotImage
+	}
+	return img, nil
+}
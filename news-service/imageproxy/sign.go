@@ -0,0 +1,39 @@
+package imageproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+)
+
+// Sign returns the signature for an upstream URL at a width. Signing both
+// stops the endpoint being used as an open proxy and pins the rendition size.
+func Sign(secret, imageURL string, width int) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(imageURL))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.Itoa(width)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Verify checks a signature in constant time
+func Verify(secret, imageURL string, width int, signature string) bool {
+	expected := Sign(secret, imageURL, width)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// URL builds a signed proxy URL. The region only picks the fallback image,
+// so it is left out of the signature.
+func (c *Config) URL(imageURL string, width int, region string) string {
+	width = c.SnapWidth(width)
+	query := url.Values{}
+	query.Set("url", imageURL)
+	query.Set("w", strconv.Itoa(width))
+	if region != "" {
+		query.Set("region", region)
+	}
+	query.Set("sig", Sign(c.Secret, imageURL, width))
+	return c.BasePath + "?" + query.Encode()
+}
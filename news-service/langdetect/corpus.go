@@ -0,0 +1,118 @@
+package langdetect
+
+// Sample text per Latin-script language. Trigram profiles are built from
+// these at startup, so extending coverage is a matter of adding text here.
+// News register is deliberate: headlines and ledes are what we classify.
+var latinCorpus = map[string]string{
+	"en": `The government said on Monday that it would introduce new measures to
+		support families and small businesses after the latest figures showed
+		prices rising faster than expected. The minister told reporters that
+		the plan was being discussed with the opposition and could be approved
+		before the end of the month. Police are investigating what happened
+		when the train was delayed for several hours. Thousands of people were
+		affected by the storm, which also caused flooding in the north of the
+		country. The company announced its quarterly results and shares fell
+		sharply in early trading. Officials have warned that the situation
+		could get worse over the weekend. The team won the match with a late
+		goal and will play in the final next week. According to the report,
+		more than half of the students had not been able to return to school.
+		Scientists say the new study shows that the climate is changing
+		faster than they thought. The president is expected to meet world
+		leaders at the summit and talk about trade, security and health.`,
+	"de": `Die Bundesregierung hat am Montag neue Maßnahmen angekündigt, um
+		Familien und kleine Unternehmen zu unterstützen, nachdem die Preise
+		stärker gestiegen sind als erwartet. Der Minister sagte, dass der Plan
+		mit der Opposition besprochen werde und noch vor Ende des Monats
+		beschlossen werden könne. Die Polizei ermittelt, was passiert ist, als
+		der Zug mehrere Stunden Verspätung hatte. Tausende Menschen waren von
+		dem Unwetter betroffen, das auch im Norden des Landes zu
+		Überschwemmungen führte. Das Unternehmen hat seine Quartalszahlen
+		vorgelegt und die Aktie ist im frühen Handel deutlich gefallen. Nach
+		Angaben des Berichts konnten mehr als die Hälfte der Schüler nicht in
+		die Schule zurückkehren. Die Mannschaft gewann das Spiel mit einem
+		späten Tor und spielt nächste Woche im Finale. Wissenschaftler sagen,
+		dass sich das Klima schneller verändert als gedacht. Der Kanzler wird
+		sich beim Gipfel mit anderen Regierungschefs treffen und über Handel,
+		Sicherheit und Gesundheit sprechen.`,
+	"fr": `Le gouvernement a annoncé lundi de nouvelles mesures pour soutenir
+		les familles et les petites entreprises après que les derniers
+		chiffres ont montré une hausse des prix plus rapide que prévu. Le
+		ministre a déclaré aux journalistes que le plan était discuté avec
+		l'opposition et pourrait être adopté avant la fin du mois. La police
+		enquête sur ce qui s'est passé lorsque le train a été retardé pendant
+		plusieurs heures. Des milliers de personnes ont été touchées par la
+		tempête, qui a aussi provoqué des inondations dans le nord du pays.
+		L'entreprise a publié ses résultats trimestriels et l'action a
+		fortement baissé en début de séance. Selon le rapport, plus de la
+		moitié des élèves n'ont pas pu retourner à l'école. L'équipe a gagné
+		le match grâce à un but tardif et jouera la finale la semaine
+		prochaine. Les scientifiques estiment que le climat change plus vite
+		qu'ils ne le pensaient. Le président doit rencontrer les dirigeants
+		lors du sommet pour parler du commerce, de la sécurité et de la santé.`,
+	"es": `El gobierno anunció el lunes nuevas medidas para apoyar a las
+		familias y a las pequeñas empresas después de que los últimos datos
+		mostraran que los precios suben más rápido de lo esperado. El ministro
+		dijo a los periodistas que el plan se está discutiendo con la
+		oposición y podría aprobarse antes de fin de mes. La policía investiga
+		lo que ocurrió cuando el tren se retrasó varias horas. Miles de
+		personas se vieron afectadas por la tormenta, que también provocó
+		inundaciones en el norte del país. La empresa presentó sus resultados
+		trimestrales y las acciones cayeron con fuerza en la primera hora de
+		la sesión. Según el informe, más de la mitad de los alumnos no
+		pudieron volver a la escuela. El equipo ganó el partido con un gol en
+		los últimos minutos y jugará la final la próxima semana. Los
+		científicos afirman que el clima está cambiando más rápido de lo que
+		pensaban. El presidente se reunirá con los líderes en la cumbre para
+		hablar de comercio, seguridad y salud.`,
+	"it": `Il governo ha annunciato lunedì nuove misure per sostenere le
+		famiglie e le piccole imprese dopo che gli ultimi dati hanno mostrato
+		prezzi in crescita più rapida del previsto. Il ministro ha detto ai
+		giornalisti che il piano è in discussione con l'opposizione e potrebbe
+		essere approvato prima della fine del mese. La polizia indaga su
+		quello che è successo quando il treno è rimasto fermo per diverse ore.
+		Migliaia di persone sono state colpite dalla tempesta, che ha causato
+		anche inondazioni nel nord del paese. L'azienda ha pubblicato i
+		risultati trimestrali e le azioni sono scese bruscamente nelle prime
+		ore di contrattazione. Secondo il rapporto, più della metà degli
+		studenti non è potuta tornare a scuola. La squadra ha vinto la partita
+		con un gol nel finale e giocherà la finale la prossima settimana. Gli
+		scienziati dicono che il clima sta cambiando più velocemente di quanto
+		pensassero. Il presidente incontrerà i leader al vertice per parlare
+		di commercio, sicurezza e salute.`,
+	"pt": `O governo anunciou na segunda-feira novas medidas para apoiar as
+		famílias e as pequenas empresas depois de os últimos dados mostrarem
+		que os preços estão a subir mais depressa do que o esperado. O
+		ministro disse aos jornalistas que o plano está a ser discutido com a
+		oposição e poderá ser aprovado antes do fim do mês. A polícia
+		investiga o que aconteceu quando o comboio ficou parado durante várias
+		horas. Milhares de pessoas foram afetadas pela tempestade, que também
+		causou inundações no norte do país. A empresa divulgou os resultados
+		trimestrais e as ações caíram fortemente no início da sessão. Segundo
+		o relatório, mais de metade dos alunos não conseguiu voltar à escola.
+		A equipa venceu o jogo com um golo nos últimos minutos e vai jogar a
+		final na próxima semana. Os cientistas dizem que o clima está a mudar
+		mais rápido do que pensavam. O presidente vai reunir-se com os
+		líderes na cimeira para falar de comércio, segurança e saúde.`,
+	"nl": `De regering heeft maandag nieuwe maatregelen aangekondigd om gezinnen
+		en kleine bedrijven te steunen nadat de laatste cijfers lieten zien
+		dat de prijzen sneller stijgen dan verwacht. De minister zei tegen
+		journalisten dat het plan met de oppositie wordt besproken en voor
+		het einde van de maand kan worden goedgekeurd. De politie onderzoekt
+		wat er gebeurde toen de trein urenlang vertraging had. Duizenden
+		mensen werden getroffen door de storm, die ook overstromingen in het
+		noorden van het land veroorzaakte. Het bedrijf heeft de
+		kwartaalcijfers gepubliceerd en het aandeel daalde flink in de
+		vroege handel. Volgens het rapport kon meer dan de helft van de
+		leerlingen niet terug naar school. Het team won de wedstrijd met een
+		late goal en speelt volgende week de finale. Wetenschappers zeggen
+		dat het klimaat sneller verandert dan ze dachten. De premier zal op
+		de top met andere leiders praten over handel, veiligheid en
+		gezondheid.`,
+}
+
+// Marker words separating languages that share the Devanagari script
+var devanagariMarkers = map[string][]string{
+	"hi": {"है", "हैं", "और", "के", "में", "की", "से", "को", "नहीं", "था", "थे", "ने", "पर", "लिए", "कहा", "गया", "रहा"},
+	"mr": {"आहे", "आहेत", "आणि", "च्या", "झाले", "नाही", "होते", "केले", "मध्ये", "साठी", "यांनी", "हे", "या"},
+	"ne": {"छ", "छन्", "र", "को", "मा", "गरेको", "भएको", "गर्न", "हो", "पनि", "लागि", "थियो"},
+}
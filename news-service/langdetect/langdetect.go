@@ -0,0 +1,272 @@
+// Package langdetect identifies the language of short news text without any
+// external service. Non-Latin scripts are classified by script (with marker
+// words where scripts are shared); Latin text is scored against character
+// trigram profiles built from the embedded corpus.
+package langdetect
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Unknown is returned when the text is too short or ambiguous to classify
+const Unknown = ""
+
+// minLetters is the least text we will try to classify
+const minLetters = 12
+
+// Result is a detected language with a 0..1 confidence
+type Result struct {
+	Lang       string  `json:"lang"`
+	Confidence float64 `json:"confidence"`
+}
+
+type profile struct {
+	counts map[string]float64
+	total  float64
+}
+
+var (
+	profiles   map[string]*profile
+	vocabulary float64
+)
+
+func init() {
+	profiles = make(map[string]*profile, len(latinCorpus))
+	seen := make(map[string]bool)
+	for lang, text := range latinCorpus {
+		p := &profile{counts: make(map[string]float64)}
+		for _, gram := range trigrams(text) {
+			p.counts[gram]++
+			p.total++
+			seen[gram] = true
+		}
+		profiles[lang] = p
+	}
+	vocabulary = float64(len(seen))
+}
+
+// Detect returns the most likely language of text
+func Detect(text string) Result {
+	script, share, letters := dominantScript(text)
+	if letters < minLetters {
+		return Result{Lang: Unknown}
+	}
+
+	switch script {
+	case "latin":
+		return detectLatin(text)
+	case "devanagari":
+		return Result{Lang: detectDevanagari(text), Confidence: share}
+	case "arabic":
+		// Letters used by Urdu but not Arabic
+		if strings.ContainsAny(text, "ٹڈڑںےگچپ") {
+			return Result{Lang: "ur", Confidence: share}
+		}
+		return Result{Lang: "ar", Confidence: share}
+	case "cyrillic":
+		if strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+			return Result{Lang: "uk", Confidence: share}
+		}
+		return Result{Lang: "ru", Confidence: share}
+	case "han":
+		if strings.IndexFunc(text, isKana) >= 0 {
+			return Result{Lang: "ja", Confidence: share}
+		}
+		return Result{Lang: "zh", Confidence: share}
+	case "":
+		return Result{Lang: Unknown}
+	default:
+		return Result{Lang: scriptLanguages[script], Confidence: share}
+	}
+}
+
+// DetectOr returns the detected language, or fallback when detection fails
+// or is not confident
+func DetectOr(text, fallback string) string {
+	if result := Detect(text); result.Lang != Unknown && result.Confidence >= minConfidence {
+		return result.Lang
+	}
+	return fallback
+}
+
+// Scripts with a single language in our feeds
+var scriptLanguages = map[string]string{
+	"bengali":   "bn",
+	"gurmukhi":  "pa",
+	"gujarati":  "gu",
+	"tamil":     "ta",
+	"telugu":    "te",
+	"kannada":   "kn",
+	"malayalam": "ml",
+	"hangul":    "ko",
+	"greek":     "el",
+	"hebrew":    "he",
+	"thai":      "th",
+}
+
+var scriptTables = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"latin", unicode.Latin},
+	{"devanagari", unicode.Devanagari},
+	{"bengali", unicode.Bengali},
+	{"gurmukhi", unicode.Gurmukhi},
+	{"gujarati", unicode.Gujarati},
+	{"tamil", unicode.Tamil},
+	{"telugu", unicode.Telugu},
+	{"kannada", unicode.Kannada},
+	{"malayalam", unicode.Malayalam},
+	{"arabic", unicode.Arabic},
+	{"cyrillic", unicode.Cyrillic},
+	{"han", unicode.Han},
+	{"han", unicode.Hiragana},
+	{"han", unicode.Katakana},
+	{"hangul", unicode.Hangul},
+	{"greek", unicode.Greek},
+	{"hebrew", unicode.Hebrew},
+	{"thai", unicode.Thai},
+}
+
+// dominantScript returns the script most letters belong to, its share of
+// the letters and the letter count
+func dominantScript(text string) (string, float64, int) {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && !unicode.Is(unicode.Mc, r) {
+			continue
+		}
+		letters++
+		for _, s := range scriptTables {
+			if unicode.Is(s.table, r) {
+				counts[s.name]++
+				break
+			}
+		}
+	}
+
+	best, bestCount := "", 0
+	for name, count := range counts {
+		if count > bestCount || (count == bestCount && name < best) {
+			best, bestCount = name, count
+		}
+	}
+	if letters == 0 {
+		return "", 0, 0
+	}
+	return best, float64(bestCount) / float64(letters), letters
+}
+
+func isKana(r rune) bool {
+	return unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
+}
+
+// detectDevanagari separates Hindi, Marathi and Nepali by marker words,
+// defaulting to Hindi which dominates our Indian feeds
+func detectDevanagari(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || r == '।'
+	})
+
+	scores := make(map[string]int)
+	for _, word := range words {
+		for lang, markers := range devanagariMarkers {
+			for _, marker := range markers {
+				if word == marker {
+					scores[lang]++
+				}
+			}
+		}
+	}
+
+	best, bestScore := "hi", scores["hi"]
+	for _, lang := range []string{"mr", "ne"} {
+		if scores[lang] > bestScore {
+			best, bestScore = lang, scores[lang]
+		}
+	}
+	return best
+}
+
+// detectLatin scores text against each trigram profile with add-one
+// smoothing and converts the log-likelihoods into a confidence
+func detectLatin(text string) Result {
+	grams := trigrams(text)
+	if len(grams) == 0 {
+		return Result{Lang: Unknown}
+	}
+
+	type scored struct {
+		lang  string
+		score float64
+	}
+	scores := make([]scored, 0, len(profiles))
+	for lang, p := range profiles {
+		logProb := 0.0
+		for _, gram := range grams {
+			logProb += math.Log((p.counts[gram] + 1) / (p.total + vocabulary))
+		}
+		// Average per trigram so confidence doesn't depend on text length
+		scores = append(scores, scored{lang, logProb / float64(len(grams))})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].lang < scores[j].lang
+	})
+
+	// Softmax over the per-trigram scores, sharpened so a clear winner
+	// approaches 1 while near-ties stay near 1/n
+	sum := 0.0
+	for _, s := range scores {
+		sum += math.Exp(8 * (s.score - scores[0].score))
+	}
+	return Result{Lang: scores[0].lang, Confidence: 1 / sum}
+}
+
+// trigrams lowercases text and returns character trigrams of each word,
+// padded with spaces so word starts and ends are distinctive
+func trigrams(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	var grams []string
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+3]))
+		}
+	}
+	return grams
+}
+
+// minConfidence is the least confidence DetectOr trusts over its fallback
+const minConfidence = 0.4
+
+// Default languages of the regions we ingest, used when detection fails
+var regionLanguages = map[string]string{
+	"us": "en",
+	"gb": "en",
+	"ca": "en",
+	"au": "en",
+	"in": "en",
+	"de": "de",
+	"at": "de",
+	"fr": "fr",
+	"es": "es",
+	"it": "it",
+	"br": "pt",
+	"nl": "nl",
+}
+
+// ForArticle detects the language of an article's title and description,
+// falling back to the region's main language
+func ForArticle(title, description, region string) string {
+	return DetectOr(title+". "+description, regionLanguages[strings.ToLower(region)])
+}
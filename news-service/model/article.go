@@ -11,7 +11,18 @@ type Article struct {
 	Source      struct {
 		Name string `json:"name" bson:"name"`
 	} `json:"source" bson:"source"`
-	PublishedAt time.Time `json:"publishedAt" bson:"publishedAt"`
-	Topic       string    `json:"topic" bson:"topic"`
-	FetchedAt   time.Time `json:"fetchedAt" bson:"fetchedAt"`
+	PublishedAt        time.Time   `json:"publishedAt" bson:"publishedAt"`
+	Topic              string      `json:"topic" bson:"topic"`
+	Lang               string      `json:"lang,omitempty" bson:"lang,omitempty"`
+	Category           string      `json:"category,omitempty" bson:"category,omitempty"`
+	CategoryConfidence float64     `json:"categoryConfidence,omitempty" bson:"categoryConfidence,omitempty"` // Classifier posterior, 0..1
+	Entities           []EntityRef `json:"entities,omitempty" bson:"entities,omitempty"`
+	FetchedAt          time.Time   `json:"fetchedAt" bson:"fetchedAt"`
+}
+
+// EntityRef is a person, organisation or place mentioned in an article
+type EntityRef struct {
+	ID   string `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+	Type string `json:"type" bson:"type"`
 }
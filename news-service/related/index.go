@@ -0,0 +1,288 @@
+package related
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Document is an indexed article with its term frequencies
+type Document struct {
+	ID          string
+	Region      string
+	PublishedAt time.Time
+	FetchedAt   time.Time
+	terms       map[string]float64 // Normalised term frequency
+}
+
+// Match is a document scored against a query
+type Match struct {
+	ID         string
+	Similarity float64
+}
+
+// Filter restricts which documents a query may return
+type Filter func(doc *Document) bool
+
+// Index is an in-memory TF-IDF index over article titles and descriptions.
+// Document frequencies are kept incrementally so articles can be added and
+// removed without a rebuild; IDF is evaluated at query time.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*Document
+	postings map[string]map[string]struct{} // term -> doc IDs
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*Document),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// Add indexes or replaces a document
+func (idx *Index) Add(doc Document, title, description string) {
+	doc.terms = TermFrequencies(title, description)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(doc.ID)
+	if len(doc.terms) == 0 {
+		return
+	}
+
+	idx.docs[doc.ID] = &doc
+	for term := range doc.terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]struct{})
+		}
+		idx.postings[term][doc.ID] = struct{}{}
+	}
+}
+
+// Remove drops a document from the index
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(id)
+}
+
+func (idx *Index) removeLocked(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// Prune removes documents fetched before cutoff and returns how many went
+func (idx *Index) Prune(cutoff time.Time) int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	removed := 0
+	for id, doc := range idx.docs {
+		if doc.FetchedAt.Before(cutoff) {
+			idx.removeLocked(id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Size returns the number of indexed documents and distinct terms
+func (idx *Index) Size() (docs int, terms int) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs), len(idx.postings)
+}
+
+// Get returns an indexed document's metadata
+func (idx *Index) Get(id string) (Document, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	doc, ok := idx.docs[id]
+	if !ok {
+		return Document{}, false
+	}
+	return *doc, true
+}
+
+// TermsOf returns the term frequencies of an indexed document
+func (idx *Index) TermsOf(id string) (map[string]float64, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	doc, ok := idx.docs[id]
+	if !ok {
+		return nil, false
+	}
+	return doc.terms, true
+}
+
+// Search returns documents ranked by cosine similarity to query, best first.
+// Only documents sharing at least one term with the query are scored.
+func (idx *Index) Search(query map[string]float64, filter Filter, limit int) []Match {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	total := float64(len(idx.docs))
+	if total == 0 || len(query) == 0 {
+		return nil
+	}
+
+	queryVec := idx.weightLocked(query, total)
+	queryNorm := norm(queryVec)
+	if queryNorm == 0 {
+		return nil
+	}
+
+	dots := make(map[string]float64)
+	for term, qw := range queryVec {
+		idf := idx.idfLocked(term, total)
+		for id := range idx.postings[term] {
+			dots[id] += qw * idx.docs[id].terms[term] * idf
+		}
+	}
+
+	matches := make([]Match, 0, len(dots))
+	for id, dot := range dots {
+		doc := idx.docs[id]
+		if filter != nil && !filter(doc) {
+			continue
+		}
+		docNorm := norm(idx.weightLocked(doc.terms, total))
+		if docNorm == 0 {
+			continue
+		}
+		matches = append(matches, Match{ID: id, Similarity: dot / (queryNorm * docNorm)})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Similarity returns the cosine similarity of two indexed documents
+func (idx *Index) Similarity(a, b string) float64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	docA, okA := idx.docs[a]
+	docB, okB := idx.docs[b]
+	if !okA || !okB {
+		return 0
+	}
+
+	total := float64(len(idx.docs))
+	vecA := idx.weightLocked(docA.terms, total)
+	vecB := idx.weightLocked(docB.terms, total)
+
+	dot := 0.0
+	for term, w := range vecA {
+		dot += w * vecB[term]
+	}
+	normA, normB := norm(vecA), norm(vecB)
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (normA * normB)
+}
+
+func (idx *Index) weightLocked(terms map[string]float64, total float64) map[string]float64 {
+	vec := make(map[string]float64, len(terms))
+	for term, tf := range terms {
+		vec[term] = tf * idx.idfLocked(term, total)
+	}
+	return vec
+}
+
+// idfLocked uses smoothed IDF so terms unseen by the index still count
+func (idx *Index) idfLocked(term string, total float64) float64 {
+	df := float64(len(idx.postings[term]))
+	return math.Log((1+total)/(1+df)) + 1
+}
+
+func norm(vec map[string]float64) float64 {
+	sum := 0.0
+	for _, w := range vec {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+// TermFrequencies tokenises title and description into normalised term
+// frequencies. Title terms count double since they carry the story.
+func TermFrequencies(title, description string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, token := range tokenize(title) {
+		counts[token] += 2
+	}
+	for _, token := range tokenize(description) {
+		counts[token]++
+	}
+
+	max := 0.0
+	for _, c := range counts {
+		max = math.Max(max, c)
+	}
+	for term, c := range counts {
+		counts[term] = 0.5 + 0.5*c/max
+	}
+	return counts
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "that": true,
+	"this": true, "are": true, "was": true, "were": true, "has": true, "have": true,
+	"had": true, "not": true, "but": true, "you": true, "your": true, "its": true,
+	"our": true, "their": true, "they": true, "them": true, "his": true, "her": true,
+	"she": true, "him": true, "into": true, "over": true, "after": true, "before": true,
+	"about": true, "what": true, "how": true, "why": true, "who": true, "when": true,
+	"will": true, "would": true, "can": true, "could": true, "said": true, "says": true,
+	"more": true, "than": true, "out": true, "all": true, "also": true, "been": true,
+	"being": true, "which": true, "while": true, "there": true, "here": true, "just": true,
+	"news": true, "latest": true, "read": true, "chars": true,
+	// German and Hindi-transliterated fillers seen in regional feeds
+	"der": true, "die": true, "das": true, "und": true, "mit": true, "von": true,
+	"für": true, "ist": true, "ein": true, "eine": true, "auf": true, "den": true,
+	"ke": true, "ki": true, "ka": true, "hai": true,
+}
+
+func tokenize(text string) []string {
+	// Marks stay inside words: Devanagari vowel signs and viramas are not letters
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 3 || stopWords[field] {
+			continue
+		}
+		// Fold simple English plurals so "election" matches "elections"
+		if len(field) > 4 && strings.HasSuffix(field, "s") && !strings.HasSuffix(field, "ss") {
+			field = strings.TrimSuffix(field, "s")
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
@@ -0,0 +1,43 @@
+package related
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "english",
+			text: "Elections: what the latest polls say about the race",
+			want: []string{"election", "poll", "say", "race"},
+		},
+		{
+			name: "german",
+			text: "Der Bundestag stimmt über das Heizungsgesetz ab",
+			want: []string{"bundestag", "stimmt", "über", "heizungsgesetz"},
+		},
+		{
+			name: "hindi keeps vowel signs and viramas inside words",
+			text: "भारत ने चुनाव में दर्ज की ऐतिहासिक जीत",
+			want: []string{"भारत", "चुनाव", "में", "दर्ज", "ऐतिहासिक", "जीत"},
+		},
+		{
+			name: "digits",
+			text: "G20 summit 2026",
+			want: []string{"g20", "summit", "2026"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenize(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
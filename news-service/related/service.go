@@ -0,0 +1,360 @@
+package related
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound is returned when the source article doesn't exist
+var ErrNotFound = errors.New("article not found")
+
+// Config controls index retention and match thresholds
+type Config struct {
+	Retention          time.Duration // Articles fetched earlier drop out of the index
+	SyncInterval       time.Duration // How often new articles are pulled in
+	MinSimilarity      float64       // Matches below this are noise
+	DuplicateThreshold float64       // Matches above this are the same story
+	DefaultLimit       int
+	MaxLimit           int
+}
+
+// LoadConfig reads related-articles configuration from the environment
+func LoadConfig() *Config {
+	return &Config{
+		Retention:          time.Duration(getEnvIntOrDefault("RELATED_RETENTION_DAYS", 14)) * 24 * time.Hour,
+		SyncInterval:       time.Duration(getEnvIntOrDefault("RELATED_SYNC_INTERVAL_SECONDS", 60)) * time.Second,
+		MinSimilarity:      getEnvFloatOrDefault("RELATED_MIN_SIMILARITY", 0.08),
+		DuplicateThreshold: getEnvFloatOrDefault("RELATED_DUPLICATE_THRESHOLD", 0.75),
+		DefaultLimit:       10,
+		MaxLimit:           50,
+	}
+}
+
+// Query describes a related-articles lookup
+type Query struct {
+	Limit  int
+	Region string        // Restrict matches to this topic; empty means any
+	MaxAge time.Duration // Ignore matches published longer ago; 0 means no limit
+}
+
+// Article is a related article with its similarity to the source
+type Article struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	URL         string    `json:"url"`
+	Image       string    `json:"image"`
+	Source      string    `json:"source"`
+	Region      string    `json:"region"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Similarity  float64   `json:"similarity"`
+}
+
+type articleDoc struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Title       string             `bson:"title"`
+	Description string             `bson:"description"`
+	URL         string             `bson:"url"`
+	Image       string             `bson:"image"`
+	Source      struct {
+		Name string `bson:"name"`
+	} `bson:"source"`
+	PublishedAt time.Time `bson:"publishedAt"`
+	Topic       string    `bson:"topic"`
+	FetchedAt   time.Time `bson:"fetchedAt"`
+}
+
+// Service keeps the index in step with the articles collection and answers
+// related-article queries
+type Service struct {
+	collection *mongo.Collection
+	config     *Config
+	index      *Index
+
+	mu        sync.RWMutex
+	watermark time.Time // Latest fetchedAt seen by sync
+}
+
+func NewService(collection *mongo.Collection, config *Config) *Service {
+	s := &Service{
+		collection: collection,
+		config:     config,
+		index:      NewIndex(),
+	}
+	s.ensureIndexes()
+	return s
+}
+
+// ensureIndexes backs the incremental sync query
+func (s *Service) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "fetchedAt", Value: 1}},
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to create fetchedAt index: %v", err)
+	}
+}
+
+// Config returns the service configuration
+func (s *Service) Config() *Config {
+	return s.config
+}
+
+// Start loads the retention window and then polls for newly stored articles.
+// The fetcher upserts and bumps fetchedAt, so updated articles are re-indexed too.
+func (s *Service) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.watermark = time.Now().Add(-s.config.Retention)
+	s.mu.Unlock()
+	s.sync(ctx)
+
+	ticker := time.NewTicker(s.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sync(ctx)
+		}
+	}
+}
+
+func (s *Service) sync(ctx context.Context) {
+	start := time.Now()
+	syncCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "fetchedAt", Value: 1}}).
+		SetProjection(bson.M{"title": 1, "description": 1, "topic": 1, "publishedAt": 1, "fetchedAt": 1})
+
+	s.mu.RLock()
+	watermark := s.watermark
+	s.mu.RUnlock()
+
+	cursor, err := s.collection.Find(syncCtx, bson.M{"fetchedAt": bson.M{"$gt": watermark}}, opts)
+	if err != nil {
+		log.Printf("Failed to sync related index: %v", err)
+		return
+	}
+	defer cursor.Close(syncCtx)
+
+	added := 0
+	for cursor.Next(syncCtx) {
+		var doc articleDoc
+		if err := cursor.Decode(&doc); err != nil {
+			log.Printf("Failed to decode article for related index: %v", err)
+			continue
+		}
+
+		s.index.Add(Document{
+			ID:          doc.ID.Hex(),
+			Region:      doc.Topic,
+			PublishedAt: doc.PublishedAt,
+			FetchedAt:   doc.FetchedAt,
+		}, doc.Title, doc.Description)
+
+		if doc.FetchedAt.After(watermark) {
+			watermark = doc.FetchedAt
+		}
+		added++
+	}
+	if err := cursor.Err(); err != nil {
+		log.Printf("Related index sync cursor error: %v", err)
+	}
+
+	s.mu.Lock()
+	s.watermark = watermark
+	s.mu.Unlock()
+
+	pruned := s.index.Prune(time.Now().Add(-s.config.Retention))
+	if added > 0 || pruned > 0 {
+		docs, terms := s.index.Size()
+		log.Printf("Related index synced: +%d -%d articles (%d docs, %d terms) in %v",
+			added, pruned, docs, terms, time.Since(start))
+	}
+}
+
+// Related returns articles similar to id. Near-duplicates of the source, and
+// of each other, are collapsed so one story isn't listed once per outlet.
+func (s *Service) Related(ctx context.Context, id string, q Query) ([]Article, error) {
+	terms, ok := s.index.TermsOf(id)
+	if !ok {
+		// Outside the retention window; vectorise it on the fly
+		doc, err := s.loadArticle(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		terms = TermFrequencies(doc.Title, doc.Description)
+	}
+
+	var cutoff time.Time
+	if q.MaxAge > 0 {
+		cutoff = time.Now().Add(-q.MaxAge)
+	}
+
+	filter := func(doc *Document) bool {
+		if doc.ID == id {
+			return false
+		}
+		if q.Region != "" && doc.Region != q.Region {
+			return false
+		}
+		if !cutoff.IsZero() {
+			published := doc.PublishedAt
+			if published.IsZero() {
+				published = doc.FetchedAt
+			}
+			if published.Before(cutoff) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Over-fetch so duplicate collapsing still leaves a full page
+	candidates := s.index.Search(terms, filter, q.Limit*5)
+
+	var picked []Match
+	for _, candidate := range candidates {
+		if len(picked) == q.Limit {
+			break
+		}
+		if candidate.Similarity < s.config.MinSimilarity {
+			break
+		}
+		if candidate.Similarity >= s.config.DuplicateThreshold {
+			continue
+		}
+
+		duplicate := false
+		for _, p := range picked {
+			if s.index.Similarity(candidate.ID, p.ID) >= s.config.DuplicateThreshold {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			picked = append(picked, candidate)
+		}
+	}
+
+	return s.hydrate(ctx, picked)
+}
+
+// Stats reports index size for the status endpoint
+func (s *Service) Stats() map[string]interface{} {
+	docs, terms := s.index.Size()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return map[string]interface{}{
+		"documents": docs,
+		"terms":     terms,
+		"watermark": s.watermark,
+	}
+}
+
+func (s *Service) loadArticle(ctx context.Context, id string) (*articleDoc, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var doc articleDoc
+	err = s.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load article: %w", err)
+	}
+	return &doc, nil
+}
+
+// hydrate loads full articles for matches, keeping match order. Articles
+// deleted since indexing are dropped from the index.
+func (s *Service) hydrate(ctx context.Context, matches []Match) ([]Article, error) {
+	if len(matches) == 0 {
+		return []Article{}, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(matches))
+	for _, match := range matches {
+		if objectID, err := primitive.ObjectIDFromHex(match.ID); err == nil {
+			ids = append(ids, objectID)
+		}
+	}
+
+	cursor, err := s.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load related articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []articleDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode related articles: %w", err)
+	}
+
+	byID := make(map[string]articleDoc, len(docs))
+	for _, doc := range docs {
+		byID[doc.ID.Hex()] = doc
+	}
+
+	articles := make([]Article, 0, len(matches))
+	for _, match := range matches {
+		doc, ok := byID[match.ID]
+		if !ok {
+			s.index.Remove(match.ID)
+			continue
+		}
+		articles = append(articles, Article{
+			ID:          match.ID,
+			Title:       doc.Title,
+			Description: doc.Description,
+			URL:         doc.URL,
+			Image:       doc.Image,
+			Source:      doc.Source.Name,
+			Region:      doc.Topic,
+			PublishedAt: doc.PublishedAt,
+			Similarity:  match.Similarity,
+		})
+	}
+	return articles, nil
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
@@ -9,8 +9,12 @@ import (
 	"syscall"
 	"time"
 	"video-service/config"
+	"video-service/fetcher"
+	"video-service/matrix"
 	"video-service/router"
+	"video-service/service"
 	"video-service/worker"
+	"video-service/youtube"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -29,11 +33,20 @@ func main() {
 
 	db := mongoClient.Database("videosdb")
 
+	// Real API, or recorded fixtures when YOUTUBE_FIXTURES_DIR is set
+	ytClient := youtube.New(cfg)
+
+	// The fetcher is shared by the worker and the search fallback
+	videoFetcher := fetcher.NewFetcher(cfg, db, ytClient)
+
+	// Regions and categories to fetch; shared by the scheduler and the API
+	fetchMatrix := matrix.NewStore(cfg)
+
 	// Setup router with database connection
-	r := router.Setup(db)
+	r := router.Setup(cfg, db, videoFetcher, fetchMatrix, service.New(ytClient))
 
 	// Create and start worker
-	videoWorker, err := worker.NewWorker(cfg, db)
+	videoWorker, err := worker.NewWorker(cfg, videoFetcher, fetchMatrix)
 	if err != nil {
 		log.Fatal("Failed to create worker:", err)
 	}
@@ -42,6 +55,8 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	go fetchMatrix.Watch(ctx, cfg.FetchMatrixReload)
+
 	if err := videoWorker.Start(ctx); err != nil {
 		log.Fatal("Failed to start worker:", err)
 	}
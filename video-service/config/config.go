@@ -16,6 +16,40 @@ type Config struct {
 	MaxRetries    int
 	RetryDelay    time.Duration
 	WorkerCount   int
+
+	// Fetch matrix
+	FetchMatrixFile   string
+	FetchMatrixReload time.Duration
+
+	// Format classification
+	ShortMaxDuration time.Duration
+	LongMinDuration  time.Duration
+
+	// Stats history
+	HistoryRetention time.Duration
+	VelocityMinGap   time.Duration
+
+	// Search
+	SearchMinLocalResults int
+	SearchFallback        bool
+	SearchFallbackTTL     time.Duration
+
+	// Comments
+	CommentMaxPages        int
+	CommentRefreshInterval time.Duration
+	CommentRefreshVideos   int
+
+	// Channels
+	ChannelRefreshInterval time.Duration
+
+	// Availability checks
+	AvailabilityCheckInterval time.Duration
+	AvailabilityRecheckAfter  time.Duration
+	AvailabilityMaxVideos     int
+	AvailabilityGracePeriod   time.Duration
+
+	// Serve YouTube calls from recorded fixtures instead of the API
+	YouTubeFixturesDir string
 }
 
 func Load() *Config {
@@ -28,9 +62,35 @@ func Load() *Config {
 		MaxRetries:    getIntEnv("MAX_RETRIES", 3),
 		RetryDelay:    getDurationEnv("RETRY_DELAY", "30s"),
 		WorkerCount:   getIntEnv("WORKER_COUNT", 2),
+
+		FetchMatrixFile:   getEnv("FETCH_MATRIX_FILE", ""),
+		FetchMatrixReload: getDurationEnv("FETCH_MATRIX_RELOAD_INTERVAL", "30s"),
+
+		ShortMaxDuration: getDurationEnv("SHORT_VIDEO_MAX_DURATION", "60s"),
+		LongMinDuration:  getDurationEnv("LONG_VIDEO_MIN_DURATION", "20m"),
+
+		HistoryRetention: getDurationEnv("STATS_HISTORY_RETENTION", "720h"),
+		VelocityMinGap:   getDurationEnv("VELOCITY_MIN_GAP", "30m"),
+
+		SearchMinLocalResults: getIntEnv("SEARCH_MIN_LOCAL_RESULTS", 5),
+		SearchFallback:        getBoolEnv("SEARCH_YOUTUBE_FALLBACK", true),
+		SearchFallbackTTL:     getDurationEnv("SEARCH_FALLBACK_TTL", "6h"),
+
+		CommentMaxPages:        getIntEnv("COMMENT_MAX_PAGES", 3),
+		CommentRefreshInterval: getDurationEnv("COMMENT_REFRESH_INTERVAL", "2h"),
+		CommentRefreshVideos:   getIntEnv("COMMENT_REFRESH_VIDEOS", 30),
+
+		ChannelRefreshInterval: getDurationEnv("CHANNEL_REFRESH_INTERVAL", "24h"),
+
+		AvailabilityCheckInterval: getDurationEnv("AVAILABILITY_CHECK_INTERVAL", "1h"),
+		AvailabilityRecheckAfter:  getDurationEnv("AVAILABILITY_RECHECK_AFTER", "24h"),
+		AvailabilityMaxVideos:     getIntEnv("AVAILABILITY_MAX_VIDEOS", 1000),
+		AvailabilityGracePeriod:   getDurationEnv("AVAILABILITY_GRACE_PERIOD", "72h"),
+
+		YouTubeFixturesDir: getEnv("YOUTUBE_FIXTURES_DIR", ""),
 	}
 
-	if cfg.YouTubeAPIKey == "" {
+	if cfg.YouTubeAPIKey == "" && cfg.YouTubeFixturesDir == "" {
 		log.Fatal("YOUTUBE_API_KEY is required")
 	}
 
@@ -65,3 +125,12 @@ func getIntEnv(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
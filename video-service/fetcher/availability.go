@@ -0,0 +1,204 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+	"video-service/model"
+	"video-service/youtube"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (f *Fetcher) ensureAvailabilityIndexes(ctx context.Context) {
+	collection := f.db.Collection("videos")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "availabilityCheckedAt", Value: 1}},
+		},
+		{
+			// Purge
+			Keys: bson.D{
+				{Key: "availability", Value: 1},
+				{Key: "unavailableSince", Value: 1},
+			},
+		},
+	}
+
+	for _, index := range indexes {
+		_, err := collection.Indexes().CreateOne(ctx, index)
+		if err != nil {
+			log.Printf("Warning: Failed to create availability index: %v", err)
+		}
+	}
+}
+
+// VerifyAvailability re-checks stored videos not checked within
+// AVAILABILITY_RECHECK_AFTER, oldest check first, up to AVAILABILITY_MAX_VIDEOS
+// per run at 1 quota unit per 50 videos. Dead videos are marked unavailable
+// with a reason; ones that came back are marked available again. Videos
+// unavailable for longer than the grace period are then deleted.
+func (f *Fetcher) VerifyAvailability(ctx context.Context) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"availabilityCheckedAt": bson.M{"$exists": false}},
+			{"availabilityCheckedAt": bson.M{"$lt": time.Now().Add(-f.config.AvailabilityRecheckAfter)}},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "availabilityCheckedAt", Value: 1}}).
+		SetLimit(int64(f.config.AvailabilityMaxVideos)).
+		SetProjection(bson.M{"videoId": 1, "region": 1, "unavailableSince": 1})
+
+	cursor, err := f.db.Collection("videos").Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to select videos for availability check: %v", err)
+		return
+	}
+	var videos []model.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		log.Printf("Failed to decode videos for availability check: %v", err)
+		return
+	}
+
+	checked, unavailable := 0, 0
+	for start := 0; start < len(videos); start += youtube.MaxIDsPerRequest {
+		if ctx.Err() != nil {
+			break
+		}
+		batch := videos[start:min(start+youtube.MaxIDsPerRequest, len(videos))]
+
+		n, err := f.checkAvailability(ctx, batch)
+		if err != nil {
+			// Unchecked videos stay first in line for the next run
+			log.Printf("Availability check failed, stopping this run: %v", err)
+			break
+		}
+		checked += len(batch)
+		unavailable += n
+		time.Sleep(f.config.RateLimit)
+	}
+	log.Printf("Checked availability of %d videos, %d unavailable", checked, unavailable)
+
+	f.purgeUnavailable(ctx)
+}
+
+// checkAvailability looks up one batch of at most 50 videos and stores the
+// result. It returns how many are unavailable.
+func (f *Fetcher) checkAvailability(ctx context.Context, videos []model.Video) (int, error) {
+	ids := make([]string, len(videos))
+	for i, video := range videos {
+		ids[i] = video.VideoID
+	}
+
+	items, err := f.yt.VideoStatus(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	found := make(map[string]*model.YouTubeVideoItem, len(items))
+	for i := range items {
+		found[items[i].ID] = &items[i]
+	}
+
+	now := time.Now()
+	unavailable := 0
+	var operations []mongo.WriteModel
+	for _, video := range videos {
+		var update bson.M
+		if reason := unavailableReason(found[video.VideoID], video.Region); reason != "" {
+			fields := bson.M{
+				"availability":          model.AvailabilityUnavailable,
+				"unavailableReason":     reason,
+				"availabilityCheckedAt": now,
+			}
+			// The grace period runs from the first failed check
+			if video.UnavailableSince.IsZero() {
+				fields["unavailableSince"] = now
+			}
+			update = bson.M{"$set": fields}
+			unavailable++
+		} else {
+			update = bson.M{
+				"$set":   bson.M{"availability": model.AvailabilityAvailable, "availabilityCheckedAt": now},
+				"$unset": bson.M{"unavailableReason": "", "unavailableSince": ""},
+			}
+		}
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"videoId": video.VideoID}).
+			SetUpdate(update))
+	}
+
+	if _, err := f.db.Collection("videos").BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+		return 0, err
+	}
+	return unavailable, nil
+}
+
+// unavailableReason says why a video can't be shown in its region, or returns
+// "" when it can. item is nil when videos.list didn't return the video.
+func unavailableReason(item *model.YouTubeVideoItem, region string) string {
+	if item == nil {
+		return model.ReasonDeleted
+	}
+	if item.Status.PrivacyStatus == "private" {
+		return model.ReasonPrivate
+	}
+	switch item.Status.UploadStatus {
+	case "deleted", "failed", "rejected":
+		return model.ReasonRemoved
+	}
+
+	restriction := item.ContentDetails.RegionRestriction
+	if slices.Contains(restriction.Blocked, region) ||
+		(len(restriction.Allowed) > 0 && !slices.Contains(restriction.Allowed, region)) {
+		return model.ReasonRegionBlocked
+	}
+
+	if !item.Status.Embeddable {
+		return model.ReasonNotEmbeddable
+	}
+	return ""
+}
+
+// purgeUnavailable deletes videos that stayed unavailable for the whole grace
+// period, along with their comments. Stats history expires on its own.
+func (f *Fetcher) purgeUnavailable(ctx context.Context) {
+	filter := bson.M{
+		"availability":     model.AvailabilityUnavailable,
+		"unavailableSince": bson.M{"$lt": time.Now().Add(-f.config.AvailabilityGracePeriod)},
+	}
+
+	collection := f.db.Collection("videos")
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"videoId": 1}))
+	if err != nil {
+		log.Printf("Failed to select unavailable videos for purge: %v", err)
+		return
+	}
+	var videos []model.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		log.Printf("Failed to decode unavailable videos for purge: %v", err)
+		return
+	}
+	if len(videos) == 0 {
+		return
+	}
+
+	ids := make([]string, len(videos))
+	for i, video := range videos {
+		ids[i] = video.VideoID
+	}
+
+	result, err := collection.DeleteMany(ctx, bson.M{"videoId": bson.M{"$in": ids}})
+	if err != nil {
+		log.Printf("Failed to purge unavailable videos: %v", err)
+		return
+	}
+	if _, err := f.db.Collection(commentsCollection).DeleteMany(ctx, bson.M{"videoId": bson.M{"$in": ids}}); err != nil {
+		log.Printf("Failed to purge comments of unavailable videos: %v", err)
+	}
+	log.Printf("Purged %d videos unavailable for over %v", result.DeletedCount, f.config.AvailabilityGracePeriod)
+}
@@ -0,0 +1,146 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+	"video-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUnavailableReason(t *testing.T) {
+	playable := func() *model.YouTubeVideoItem {
+		item := &model.YouTubeVideoItem{ID: "v"}
+		item.Status.PrivacyStatus = "public"
+		item.Status.UploadStatus = "processed"
+		item.Status.Embeddable = true
+		return item
+	}
+	with := func(change func(*model.YouTubeVideoItem)) *model.YouTubeVideoItem {
+		item := playable()
+		change(item)
+		return item
+	}
+
+	tests := []struct {
+		name   string
+		item   *model.YouTubeVideoItem
+		region string
+		want   string
+	}{
+		{"missing from response", nil, "US", model.ReasonDeleted},
+		{"playable", playable(), "US", ""},
+		{"unlisted", with(func(i *model.YouTubeVideoItem) { i.Status.PrivacyStatus = "unlisted" }), "US", ""},
+		{"private", with(func(i *model.YouTubeVideoItem) { i.Status.PrivacyStatus = "private" }), "US", model.ReasonPrivate},
+		{"rejected", with(func(i *model.YouTubeVideoItem) { i.Status.UploadStatus = "rejected" }), "US", model.ReasonRemoved},
+		{"deleted upload", with(func(i *model.YouTubeVideoItem) { i.Status.UploadStatus = "deleted" }), "US", model.ReasonRemoved},
+		{"live upload", with(func(i *model.YouTubeVideoItem) { i.Status.UploadStatus = "uploaded" }), "US", ""},
+		{
+			"blocked in region",
+			with(func(i *model.YouTubeVideoItem) { i.ContentDetails.RegionRestriction.Blocked = []string{"DE", "US"} }),
+			"US", model.ReasonRegionBlocked,
+		},
+		{
+			"blocked elsewhere",
+			with(func(i *model.YouTubeVideoItem) { i.ContentDetails.RegionRestriction.Blocked = []string{"DE"} }),
+			"US", "",
+		},
+		{
+			"not in allow list",
+			with(func(i *model.YouTubeVideoItem) { i.ContentDetails.RegionRestriction.Allowed = []string{"GB"} }),
+			"US", model.ReasonRegionBlocked,
+		},
+		{
+			"in allow list",
+			with(func(i *model.YouTubeVideoItem) { i.ContentDetails.RegionRestriction.Allowed = []string{"GB", "US"} }),
+			"US", "",
+		},
+		{"not embeddable", with(func(i *model.YouTubeVideoItem) { i.Status.Embeddable = false }), "US", model.ReasonNotEmbeddable},
+		{
+			// Private wins over the region check
+			"private and blocked",
+			with(func(i *model.YouTubeVideoItem) {
+				i.Status.PrivacyStatus = "private"
+				i.ContentDetails.RegionRestriction.Blocked = []string{"US"}
+			}),
+			"US", model.ReasonPrivate,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := unavailableReason(tt.item, tt.region); got != tt.want {
+			t.Errorf("%s: unavailableReason = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("batch", func(mt *mtest.T) {
+		f := newTestFetcher(testConfig(), mt.DB)
+		since := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+		stored := []model.Video{
+			{VideoID: "vid-music-001", Region: "US"},
+			{VideoID: "vid-news-004", Region: "US"},
+			// Already unavailable; the grace period keeps its start
+			{VideoID: "vid-news-004", Region: "DE", UnavailableSince: since},
+			{VideoID: "vid-private-006", Region: "US"},
+			{VideoID: "vid-gone-999", Region: "US"},
+		}
+		want := []struct {
+			reason   string
+			setSince bool
+		}{
+			{"", false},
+			{"", false},
+			{model.ReasonRegionBlocked, false},
+			{model.ReasonPrivate, true},
+			{model.ReasonDeleted, true},
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: len(stored)}))
+
+		unavailable, err := f.checkAvailability(context.Background(), stored)
+		if err != nil {
+			mt.Fatalf("checkAvailability: %v", err)
+		}
+		if unavailable != 3 {
+			mt.Errorf("unavailable = %d, want 3", unavailable)
+		}
+
+		updates := nextUpdates(mt)
+		if len(updates) != len(stored) {
+			mt.Fatalf("got %d updates, want %d", len(updates), len(stored))
+		}
+		for i, update := range updates {
+			id := stored[i].VideoID
+			if got := update.Q.Lookup("videoId").StringValue(); got != id {
+				mt.Errorf("update %d filters on %q, want %q", i, got, id)
+			}
+
+			set := update.U.Lookup("$set").Document()
+			availability := set.Lookup("availability").StringValue()
+			reason, _ := set.Lookup("unavailableReason").StringValueOK()
+			_, setSince := set.Lookup("unavailableSince").TimeOK()
+
+			if want[i].reason == "" {
+				_, cleared := update.U.Lookup("$unset").DocumentOK()
+				if availability != model.AvailabilityAvailable || !cleared {
+					mt.Errorf("%s (%s): availability %q, $unset %v; want available and cleared", id, stored[i].Region, availability, cleared)
+				}
+				continue
+			}
+			if availability != model.AvailabilityUnavailable || reason != want[i].reason {
+				mt.Errorf("%s (%s): %q %q, want unavailable %q", id, stored[i].Region, availability, reason, want[i].reason)
+			}
+			if setSince != want[i].setSince {
+				mt.Errorf("%s (%s): sets unavailableSince %v, want %v", id, stored[i].Region, setSince, want[i].setSince)
+			}
+		}
+	})
+}
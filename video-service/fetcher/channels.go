@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"video-service/model"
+	"video-service/youtube"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const channelsCollection = "channels"
+
+// storeChannels upserts the channels behind the given videos. Channels
+// refreshed within CHANNEL_REFRESH_INTERVAL are skipped; subscriber counts
+// don't move fast enough to spend quota on every fetch.
+func (f *Fetcher) storeChannels(ctx context.Context, videos []model.Video) error {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, video := range videos {
+		if video.ChannelID != "" && !seen[video.ChannelID] {
+			seen[video.ChannelID] = true
+			ids = append(ids, video.ChannelID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	collection := f.db.Collection(channelsCollection)
+	fresh, err := collection.Distinct(ctx, "_id", bson.M{
+		"_id":       bson.M{"$in": ids},
+		"fetchedAt": bson.M{"$gte": time.Now().Add(-f.config.ChannelRefreshInterval)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check channel freshness: %w", err)
+	}
+	for _, id := range fresh {
+		if id, ok := id.(string); ok {
+			delete(seen, id)
+		}
+	}
+
+	stale := ids[:0]
+	for _, id := range ids {
+		if seen[id] {
+			stale = append(stale, id)
+		}
+	}
+
+	for start := 0; start < len(stale); start += youtube.MaxIDsPerRequest {
+		batch := stale[start:min(start+youtube.MaxIDsPerRequest, len(stale))]
+		channels, err := f.fetchChannels(ctx, batch)
+		if err != nil {
+			return err
+		}
+
+		var operations []mongo.WriteModel
+		for _, channel := range channels {
+			operations = append(operations, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"_id": channel.ID}).
+				SetReplacement(channel).
+				SetUpsert(true))
+		}
+		if len(operations) == 0 {
+			continue
+		}
+		if _, err := collection.BulkWrite(ctx, operations); err != nil {
+			return fmt.Errorf("failed to store channels: %w", err)
+		}
+	}
+	return nil
+}
+
+func (f *Fetcher) fetchChannels(ctx context.Context, ids []string) ([]model.Channel, error) {
+	response, err := f.yt.Channels(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch channels: %w", err)
+	}
+
+	now := time.Now()
+	channels := make([]model.Channel, 0, len(response.Items))
+	for _, item := range response.Items {
+		subscribers, _ := strconv.ParseInt(item.Statistics.SubscriberCount, 10, 64)
+		videoCount, _ := strconv.ParseInt(item.Statistics.VideoCount, 10, 64)
+		viewCount, _ := strconv.ParseInt(item.Statistics.ViewCount, 10, 64)
+
+		channels = append(channels, model.Channel{
+			ID:              item.ID,
+			Title:           item.Snippet.Title,
+			Description:     item.Snippet.Description,
+			CustomURL:       item.Snippet.CustomURL,
+			Thumbnail:       f.getBestThumbnail(item.Snippet.Thumbnails),
+			Country:         item.Snippet.Country,
+			SubscriberCount: subscribers,
+			VideoCount:      videoCount,
+			ViewCount:       viewCount,
+			FetchedAt:       now,
+		})
+	}
+	return channels, nil
+}
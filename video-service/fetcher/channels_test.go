@@ -0,0 +1,90 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"video-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestStoreChannels(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	const (
+		music = "UCfixtureMusic000000000"
+		show  = "UCfixtureShow0000000000"
+		news  = "UCfixtureNews0000000000"
+	)
+
+	tests := []struct {
+		name  string
+		fresh bson.A
+		want  []string
+	}{
+		{"all stale", bson.A{}, []string{music, show, news}},
+		{"one fresh", bson.A{news}, []string{music, show}},
+		{"all fresh", bson.A{music, show, news}, nil},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			f := newTestFetcher(testConfig(), mt.DB)
+			videos := []model.Video{
+				{VideoID: "vid-music-001", ChannelID: music},
+				{VideoID: "vid-ent-002", ChannelID: show},
+				{VideoID: "vid-short-003", ChannelID: show},
+				{VideoID: "vid-live-007"},
+				{VideoID: "vid-news-004", ChannelID: news},
+			}
+
+			mt.AddMockResponses(
+				mtest.CreateSuccessResponse(bson.E{Key: "values", Value: tt.fresh}),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: len(tt.want)}),
+			)
+
+			if err := f.storeChannels(context.Background(), videos); err != nil {
+				mt.Fatalf("storeChannels: %v", err)
+			}
+
+			// Each channel is asked for once; Fixture Show has two videos and one
+			// video has no channel
+			var distinct struct {
+				Query bson.Raw `bson:"query"`
+			}
+			if err := bson.Unmarshal(nextCommand(mt, "distinct"), &distinct); err != nil {
+				mt.Fatalf("decode distinct: %v", err)
+			}
+			asked, _ := distinct.Query.Lookup("_id", "$in").Array().Values()
+			if len(asked) != 3 {
+				mt.Errorf("freshness check for %d channels, want 3", len(asked))
+			}
+
+			if tt.want == nil {
+				if started := mt.GetStartedEvent(); started != nil {
+					mt.Errorf("unexpected %s command with every channel fresh", started.CommandName)
+				}
+				return
+			}
+
+			updates := nextUpdates(mt)
+			if len(updates) != len(tt.want) {
+				mt.Fatalf("got %d channel writes, want %d", len(updates), len(tt.want))
+			}
+			for i, update := range updates {
+				var channel model.Channel
+				if err := bson.Unmarshal(update.U, &channel); err != nil {
+					mt.Fatalf("decode channel %d: %v", i, err)
+				}
+				if channel.ID != tt.want[i] || !update.Upsert {
+					mt.Errorf("write %d: channel %q upsert %v, want %q upsert", i, channel.ID, update.Upsert, tt.want[i])
+				}
+				if channel.SubscriberCount == 0 || channel.Thumbnail == "" || channel.FetchedAt.IsZero() {
+					mt.Errorf("channel %s not filled in: %+v", channel.ID, channel)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"video-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const commentsCollection = "comments"
+
+// maxCommentPages caps COMMENT_MAX_PAGES; each page is 100 threads and one
+// quota unit, and popular videos have thousands of pages
+const maxCommentPages = 10
+
+// relevanceUnranked sorts comments that dropped out of the latest relevance
+// window after the ranked ones
+const relevanceUnranked = 1 << 30
+
+func (f *Fetcher) ensureCommentIndexes(ctx context.Context) {
+	collection := f.db.Collection(commentsCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "videoId", Value: 1},
+				{Key: "parentId", Value: 1},
+				{Key: "publishedAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "videoId", Value: 1},
+				{Key: "parentId", Value: 1},
+				{Key: "relevance", Value: 1},
+			},
+		},
+	}
+
+	for _, index := range indexes {
+		_, err := collection.Indexes().CreateOne(ctx, index)
+		if err != nil {
+			log.Printf("Warning: Failed to create comment index: %v", err)
+		}
+	}
+}
+
+// RefreshComments stores up to maxPages pages of comment threads for a video
+// in relevance order and returns how many comments were written. The page
+// count is capped by COMMENT_MAX_PAGES and maxCommentPages.
+func (f *Fetcher) RefreshComments(ctx context.Context, videoID string, maxPages int) (int, error) {
+	maxPages = min(maxPages, f.config.CommentMaxPages, maxCommentPages)
+	start := time.Now()
+
+	var operations []mongo.WriteModel
+	pageToken := ""
+	rank := 0
+
+	for page := 0; page < maxPages; page++ {
+		response, err := f.yt.CommentThreads(ctx, videoID, pageToken)
+		if err != nil {
+			if page == 0 {
+				return 0, fmt.Errorf("failed to fetch comments for %s: %w", videoID, err)
+			}
+			// Keep the pages we already have
+			log.Printf("Failed to fetch comment page %d for %s: %v", page+1, videoID, err)
+			break
+		}
+
+		for _, thread := range response.Items {
+			top := toVideoComment(thread.Snippet.TopLevelComment, videoID, start)
+			top.ReplyCount = thread.Snippet.TotalReplyCount
+			top.Relevance = rank
+			rank++
+			operations = append(operations, upsertComment(top))
+
+			for _, reply := range thread.Replies.Comments {
+				comment := toVideoComment(reply, videoID, start)
+				comment.ParentID = top.ID
+				operations = append(operations, upsertComment(comment))
+			}
+		}
+
+		pageToken = response.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	collection := f.db.Collection(commentsCollection)
+	if len(operations) > 0 {
+		if _, err := collection.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+			return 0, fmt.Errorf("failed to store comments for %s: %w", videoID, err)
+		}
+	}
+
+	// Older comments are kept but no longer carry a relevance rank
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"videoId": videoID, "parentId": "", "fetchedAt": bson.M{"$lt": start}},
+		bson.M{"$set": bson.M{"relevance": relevanceUnranked}})
+	if err != nil {
+		log.Printf("Failed to reset relevance for %s: %v", videoID, err)
+	}
+
+	_, err = f.db.Collection("videos").UpdateOne(ctx,
+		bson.M{"videoId": videoID},
+		bson.M{"$set": bson.M{"commentsFetchedAt": start}})
+	if err != nil {
+		log.Printf("Failed to mark comments fetched for %s: %v", videoID, err)
+	}
+
+	log.Printf("Stored %d comments for video %s", len(operations), videoID)
+	return len(operations), nil
+}
+
+// RefreshTrendingComments refreshes comments for the fastest-growing videos
+// whose comments are older than the refresh interval
+func (f *Fetcher) RefreshTrendingComments(ctx context.Context) {
+	filter := bson.M{
+		"origin":       bson.M{"$ne": model.OriginSearch},
+		"availability": bson.M{"$ne": model.AvailabilityUnavailable},
+		"$or": []bson.M{
+			{"commentsFetchedAt": bson.M{"$exists": false}},
+			{"commentsFetchedAt": bson.M{"$lt": time.Now().Add(-f.config.CommentRefreshInterval)}},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "viewVelocity", Value: -1}}).
+		SetLimit(int64(f.config.CommentRefreshVideos)).
+		SetProjection(bson.M{"videoId": 1})
+
+	cursor, err := f.db.Collection("videos").Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to select videos for comment refresh: %v", err)
+		return
+	}
+	var videos []model.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		log.Printf("Failed to decode videos for comment refresh: %v", err)
+		return
+	}
+
+	refreshed := 0
+	for _, video := range videos {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := f.RefreshComments(ctx, video.VideoID, f.config.CommentMaxPages); err != nil {
+			log.Printf("Comment refresh failed: %v", err)
+		} else {
+			refreshed++
+		}
+		time.Sleep(f.config.RateLimit)
+	}
+	log.Printf("Refreshed comments for %d of %d trending videos", refreshed, len(videos))
+}
+
+func toVideoComment(comment model.Comment, videoID string, fetchedAt time.Time) model.VideoComment {
+	publishedAt, _ := time.Parse(time.RFC3339, comment.Snippet.PublishedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, comment.Snippet.UpdatedAt)
+	return model.VideoComment{
+		ID:          comment.ID,
+		VideoID:     videoID,
+		ParentID:    comment.Snippet.ParentID,
+		Author:      comment.Snippet.AuthorDisplayName,
+		AuthorImage: comment.Snippet.AuthorProfileImageURL,
+		Text:        comment.Snippet.TextDisplay,
+		LikeCount:   comment.Snippet.LikeCount,
+		Relevance:   relevanceUnranked,
+		PublishedAt: publishedAt,
+		UpdatedAt:   updatedAt,
+		FetchedAt:   fetchedAt,
+	}
+}
+
+func upsertComment(comment model.VideoComment) mongo.WriteModel {
+	return mongo.NewReplaceOneModel().
+		SetFilter(bson.M{"_id": comment.ID}).
+		SetReplacement(comment).
+		SetUpsert(true)
+}
@@ -0,0 +1,116 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"video-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRefreshComments(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	// comments.json holds two threads, the first with two replies, and points
+	// at comments-page2.json with one more thread
+	tests := []struct {
+		name          string
+		maxPages      int
+		configPages   int
+		wantIDs       []string
+		wantRelevance map[string]int
+	}{
+		{
+			name:        "one page",
+			maxPages:    1,
+			configPages: 3,
+			wantIDs:     []string{"Ugthread001", "Ugthread001.reply01", "Ugthread001.reply02", "Ugthread002"},
+			wantRelevance: map[string]int{
+				"Ugthread001": 0,
+				"Ugthread002": 1,
+			},
+		},
+		{
+			name:        "all pages",
+			maxPages:    5,
+			configPages: 3,
+			wantIDs:     []string{"Ugthread001", "Ugthread001.reply01", "Ugthread001.reply02", "Ugthread002", "Ugthread003"},
+			wantRelevance: map[string]int{
+				"Ugthread001": 0,
+				"Ugthread002": 1,
+				"Ugthread003": 2,
+			},
+		},
+		{
+			name:        "capped by COMMENT_MAX_PAGES",
+			maxPages:    5,
+			configPages: 1,
+			wantIDs:     []string{"Ugthread001", "Ugthread001.reply01", "Ugthread001.reply02", "Ugthread002"},
+			wantRelevance: map[string]int{
+				"Ugthread001": 0,
+				"Ugthread002": 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			cfg := testConfig()
+			cfg.CommentMaxPages = tt.configPages
+			f := newTestFetcher(cfg, mt.DB)
+
+			mt.AddMockResponses(
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: len(tt.wantIDs)}),
+				mtest.CreateSuccessResponse(),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			)
+
+			stored, err := f.RefreshComments(context.Background(), "vid-music-001", tt.maxPages)
+			if err != nil {
+				mt.Fatalf("RefreshComments: %v", err)
+			}
+			if stored != len(tt.wantIDs) {
+				mt.Errorf("stored = %d, want %d", stored, len(tt.wantIDs))
+			}
+
+			updates := nextUpdates(mt)
+			if len(updates) != len(tt.wantIDs) {
+				mt.Fatalf("got %d comment writes, want %d", len(updates), len(tt.wantIDs))
+			}
+			for i, update := range updates {
+				var comment model.VideoComment
+				if err := bson.Unmarshal(update.U, &comment); err != nil {
+					mt.Fatalf("decode comment %d: %v", i, err)
+				}
+				if comment.ID != tt.wantIDs[i] || !update.Upsert {
+					mt.Errorf("write %d: id %q upsert %v, want %q upsert", i, comment.ID, update.Upsert, tt.wantIDs[i])
+				}
+				if comment.VideoID != "vid-music-001" {
+					mt.Errorf("%s: videoId %q", comment.ID, comment.VideoID)
+				}
+				if rank, top := tt.wantRelevance[comment.ID]; top {
+					if comment.Relevance != rank || comment.ParentID != "" {
+						mt.Errorf("%s: relevance %d parent %q, want %d and no parent", comment.ID, comment.Relevance, comment.ParentID, rank)
+					}
+				} else if comment.ParentID != "Ugthread001" || comment.Relevance != relevanceUnranked {
+					mt.Errorf("%s: parent %q relevance %d, want reply to Ugthread001", comment.ID, comment.ParentID, comment.Relevance)
+				}
+			}
+			if updates[0].U.Lookup("replyCount").Int32() != 2 {
+				mt.Errorf("Ugthread001 replyCount = %v, want 2", updates[0].U.Lookup("replyCount"))
+			}
+
+			// Older threads lose their rank, then the video is marked fetched
+			reset := nextUpdates(mt)
+			if len(reset) != 1 || reset[0].U.Lookup("$set", "relevance").Int32() != relevanceUnranked {
+				mt.Errorf("relevance reset = %v", reset)
+			}
+			marked := nextUpdates(mt)
+			if len(marked) != 1 || marked[0].Q.Lookup("videoId").StringValue() != "vid-music-001" {
+				mt.Errorf("commentsFetchedAt update = %v", marked)
+			}
+		})
+	}
+}
@@ -2,14 +2,14 @@ package fetcher
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"strconv"
 	"time"
 	"video-service/config"
 	"video-service/model"
+	"video-service/utils"
+	"video-service/youtube"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -19,20 +19,19 @@ import (
 type Fetcher struct {
 	config *config.Config
 	db     *mongo.Database
-	client *http.Client
+	yt     youtube.Client
 }
 
-func NewFetcher(cfg *config.Config, db *mongo.Database) *Fetcher {
+func NewFetcher(cfg *config.Config, db *mongo.Database, yt youtube.Client) *Fetcher {
 	f := &Fetcher{
 		config: cfg,
 		db:     db,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		yt:     yt,
 	}
 
 	// Ensure optimal indexes for read performance
 	f.ensureIndexes()
+	f.backfillDurations()
 	return f
 }
 
@@ -58,6 +57,43 @@ func (f *Fetcher) ensureIndexes() {
 		{
 			Keys: bson.D{{Key: "fetchedAt", Value: -1}},
 		},
+		{
+			Keys: bson.D{
+				{Key: "region", Value: 1},
+				{Key: "viewVelocity", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "region", Value: 1},
+				{Key: "format", Value: 1},
+				{Key: "viewVelocity", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "region", Value: 1},
+				{Key: "durationSeconds", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "channelId", Value: 1},
+				{Key: "publishedAt", Value: -1},
+			},
+		},
+		{
+			// Local search; titles mix languages so terms aren't stemmed
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "channelTitle", Value: "text"},
+				{Key: "description", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("video_text").
+				SetWeights(bson.M{"title": 10, "channelTitle": 5, "description": 1}).
+				SetDefaultLanguage("none"),
+		},
 	}
 
 	for _, index := range indexes {
@@ -66,6 +102,10 @@ func (f *Fetcher) ensureIndexes() {
 			log.Printf("Warning: Failed to create index: %v", err)
 		}
 	}
+
+	f.ensureHistoryIndexes(ctx)
+	f.ensureCommentIndexes(ctx)
+	f.ensureAvailabilityIndexes(ctx)
 }
 
 func (f *Fetcher) FetchVideos(ctx context.Context, req model.FetchRequest) (model.FetchResult, error) {
@@ -105,39 +145,27 @@ func (f *Fetcher) FetchVideos(ctx context.Context, req model.FetchRequest) (mode
 }
 
 func (f *Fetcher) fetchTrendingVideos(ctx context.Context, region, categoryID string, maxResults int) ([]model.Video, error) {
-	// Build YouTube API URL for trending videos
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/videos?part=snippet,statistics,contentDetails&chart=mostPopular&regionCode=%s&maxResults=%d&key=%s",
-		region, maxResults, f.config.YouTubeAPIKey)
-
-	if categoryID != "" && categoryID != "0" {
-		url += fmt.Sprintf("&videoCategoryId=%s", categoryID)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	items, err := f.yt.Trending(ctx, region, categoryID, maxResults)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	videos := f.toVideos(items, region, model.OriginTrending)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("YouTube API HTTP %d: %s", resp.StatusCode, resp.Status)
+	// Channel details are best effort; the videos are stored either way
+	if err := f.storeChannels(ctx, videos); err != nil {
+		log.Printf("Failed to store channels for region=%s, category=%s: %v", region, categoryID, err)
 	}
 
-	var apiResponse model.YouTubeVideoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, err
-	}
+	return videos, nil
+}
 
-	// Convert to our model format
+// toVideos converts videos.list items to our model format
+func (f *Fetcher) toVideos(items []model.YouTubeVideoItem, region, origin string) []model.Video {
 	var videos []model.Video
 	now := time.Now()
 
-	for _, apiVideo := range apiResponse.Items {
+	for _, apiVideo := range items {
 		// Parse published date
 		publishedAt, _ := time.Parse(time.RFC3339, apiVideo.Snippet.PublishedAt)
 
@@ -151,21 +179,27 @@ func (f *Fetcher) fetchTrendingVideos(ctx context.Context, region, categoryID st
 		// Get category name
 		categoryName := f.getCategoryName(apiVideo.Snippet.CategoryID)
 
+		durationSeconds, format := f.classifyDuration(apiVideo.ContentDetails.Duration)
+
 		video := model.Video{
-			VideoID:      apiVideo.ID,
-			Title:        apiVideo.Snippet.Title,
-			Description:  apiVideo.Snippet.Description,
-			ChannelTitle: apiVideo.Snippet.ChannelTitle,
-			CategoryID:   apiVideo.Snippet.CategoryID,
-			CategoryName: categoryName,
-			Region:       region,
-			PublishedAt:  publishedAt,
-			Thumbnail:    thumbnail,
-			VideoURL:     fmt.Sprintf("https://www.youtube.com/watch?v=%s", apiVideo.ID),
-			ViewCount:    viewCount,
-			LikeCount:    likeCount,
-			Duration:     apiVideo.ContentDetails.Duration,
-			FetchedAt:    now,
+			VideoID:         apiVideo.ID,
+			Title:           apiVideo.Snippet.Title,
+			Description:     apiVideo.Snippet.Description,
+			ChannelID:       apiVideo.Snippet.ChannelID,
+			ChannelTitle:    apiVideo.Snippet.ChannelTitle,
+			CategoryID:      apiVideo.Snippet.CategoryID,
+			CategoryName:    categoryName,
+			Region:          region,
+			PublishedAt:     publishedAt,
+			Thumbnail:       thumbnail,
+			VideoURL:        fmt.Sprintf("https://www.youtube.com/watch?v=%s", apiVideo.ID),
+			ViewCount:       viewCount,
+			LikeCount:       likeCount,
+			Duration:        apiVideo.ContentDetails.Duration,
+			DurationSeconds: durationSeconds,
+			Format:          format,
+			FetchedAt:       now,
+			Origin:          origin,
 			Source: struct {
 				Name string `json:"name" bson:"name"`
 			}{
@@ -176,7 +210,69 @@ func (f *Fetcher) fetchTrendingVideos(ctx context.Context, region, categoryID st
 		videos = append(videos, video)
 	}
 
-	return videos, nil
+	return videos
+}
+
+// classifyDuration parses a YouTube duration into seconds and a format.
+// Live streams report P0D and get no format.
+func (f *Fetcher) classifyDuration(iso string) (int64, string) {
+	duration, err := utils.ParseISODuration(iso)
+	if err != nil {
+		if iso != "" {
+			log.Printf("Unparseable video duration %q: %v", iso, err)
+		}
+		return 0, ""
+	}
+
+	seconds := int64(duration.Seconds())
+	switch {
+	case duration <= 0:
+		return 0, ""
+	case duration <= f.config.ShortMaxDuration:
+		return seconds, model.FormatShort
+	case duration >= f.config.LongMinDuration:
+		return seconds, model.FormatLong
+	default:
+		return seconds, model.FormatStandard
+	}
+}
+
+// backfillDurations classifies videos stored before durations were parsed
+func (f *Fetcher) backfillDurations() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	collection := f.db.Collection("videos")
+	cursor, err := collection.Find(ctx,
+		bson.M{"durationSeconds": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"videoId": 1, "duration": 1}))
+	if err != nil {
+		log.Printf("Warning: Failed to load videos for duration backfill: %v", err)
+		return
+	}
+
+	var videos []model.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		log.Printf("Warning: Failed to decode videos for duration backfill: %v", err)
+		return
+	}
+	if len(videos) == 0 {
+		return
+	}
+
+	var operations []mongo.WriteModel
+	for _, video := range videos {
+		seconds, format := f.classifyDuration(video.Duration)
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"videoId": video.VideoID}).
+			SetUpdate(bson.M{"$set": bson.M{"durationSeconds": seconds, "format": format}}))
+	}
+
+	if _, err := collection.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+		log.Printf("Warning: Duration backfill failed: %v", err)
+		return
+	}
+	log.Printf("Backfilled durations for %d videos", len(operations))
 }
 
 func (f *Fetcher) getBestThumbnail(thumbnails model.Thumbnails) string {
@@ -221,29 +317,49 @@ func (f *Fetcher) storeVideos(ctx context.Context, videos []model.Video, region,
 		return 0, nil
 	}
 
+	// Append to the stats history and derive velocity before overwriting counts
+	f.recordStats(ctx, videos)
+
 	collection := f.db.Collection("videos")
 
 	var operations []mongo.WriteModel
 	for _, video := range videos {
 		// Use upsert to handle duplicates
 		filter := bson.M{"videoId": video.VideoID}
-		update := bson.M{
-			"$set": bson.M{
-				"title":        video.Title,
-				"description":  video.Description,
-				"channelTitle": video.ChannelTitle,
-				"categoryId":   video.CategoryID,
-				"categoryName": video.CategoryName,
-				"region":       video.Region,
-				"publishedAt":  video.PublishedAt,
-				"thumbnail":    video.Thumbnail,
-				"videoUrl":     video.VideoURL,
-				"viewCount":    video.ViewCount,
-				"likeCount":    video.LikeCount,
-				"duration":     video.Duration,
-				"fetchedAt":    video.FetchedAt,
-				"source":       video.Source,
-			},
+		fields := bson.M{
+			"title":           video.Title,
+			"description":     video.Description,
+			"channelId":       video.ChannelID,
+			"channelTitle":    video.ChannelTitle,
+			"categoryId":      video.CategoryID,
+			"categoryName":    video.CategoryName,
+			"region":          video.Region,
+			"publishedAt":     video.PublishedAt,
+			"thumbnail":       video.Thumbnail,
+			"videoUrl":        video.VideoURL,
+			"viewCount":       video.ViewCount,
+			"likeCount":       video.LikeCount,
+			"viewVelocity":    video.ViewVelocity,
+			"likeRatio":       video.LikeRatio,
+			"duration":        video.Duration,
+			"durationSeconds": video.DurationSeconds,
+			"format":          video.Format,
+			"fetchedAt":       video.FetchedAt,
+			"source":          video.Source,
+		}
+		update := bson.M{"$set": fields}
+
+		// Search results must not demote a video that is already trending or
+		// move it to another region; a trending fetch always claims it
+		if video.Origin == model.OriginSearch {
+			delete(fields, "region")
+			update["$setOnInsert"] = bson.M{"origin": model.OriginSearch, "region": video.Region}
+		} else {
+			// The chart only lists videos playable in its region
+			fields["origin"] = model.OriginTrending
+			fields["availability"] = model.AvailabilityAvailable
+			fields["availabilityCheckedAt"] = video.FetchedAt
+			update["$unset"] = bson.M{"unavailableReason": "", "unavailableSince": ""}
 		}
 
 		operation := mongo.NewUpdateOneModel().
@@ -266,15 +382,3 @@ func (f *Fetcher) storeVideos(ctx context.Context, videos []model.Video, region,
 
 	return stored, nil
 }
-
-func (f *Fetcher) buildYouTubeURL(region, category string, maxResults int) string {
-	baseURL := "https://www.googleapis.com/youtube/v3/videos"
-	params := fmt.Sprintf("?part=snippet,statistics,contentDetails&chart=mostPopular&regionCode=%s&maxResults=%d&key=%s",
-		region, maxResults, f.config.YouTubeAPIKey)
-
-	if category != "" && category != "0" {
-		params += fmt.Sprintf("&videoCategoryId=%s", category)
-	}
-
-	return baseURL + params
-}
@@ -0,0 +1,255 @@
+package fetcher
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+	"video-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestClassifyDuration(t *testing.T) {
+	f := newTestFetcher(testConfig(), nil)
+
+	tests := []struct {
+		iso         string
+		wantSeconds int64
+		wantFormat  string
+	}{
+		{"PT45S", 45, model.FormatShort},
+		{"PT1M", 60, model.FormatShort},
+		{"PT1M1S", 61, model.FormatStandard},
+		{"PT19M59S", 1199, model.FormatStandard},
+		{"PT20M", 1200, model.FormatLong},
+		{"PT1H2M3S", 3723, model.FormatLong},
+		{"P1DT1S", 86401, model.FormatLong},
+		{"P0D", 0, ""},
+		{"", 0, ""},
+		{"4:13", 0, ""},
+	}
+
+	for _, tt := range tests {
+		seconds, format := f.classifyDuration(tt.iso)
+		if seconds != tt.wantSeconds || format != tt.wantFormat {
+			t.Errorf("classifyDuration(%q) = %d, %q; want %d, %q", tt.iso, seconds, format, tt.wantSeconds, tt.wantFormat)
+		}
+	}
+}
+
+func TestToVideos(t *testing.T) {
+	f := newTestFetcher(testConfig(), nil)
+	videos := fixtureVideos(t, f, "US", model.OriginTrending)
+
+	byID := make(map[string]model.Video, len(videos))
+	for _, video := range videos {
+		byID[video.VideoID] = video
+	}
+	if len(byID) != 4 {
+		t.Fatalf("got %d videos, want 4", len(byID))
+	}
+
+	tests := []struct {
+		id           string
+		channelID    string
+		categoryName string
+		thumbnail    string
+		views, likes int64
+		seconds      int64
+		format       string
+		publishedAt  string
+	}{
+		{
+			id:           "vid-music-001",
+			channelID:    "UCfixtureMusic000000000",
+			categoryName: "Music",
+			thumbnail:    "https://i.ytimg.com/vi/vid-music-001/hqdefault.jpg",
+			views:        2841093, likes: 190412,
+			seconds: 221, format: model.FormatStandard,
+			publishedAt: "2026-10-15T16:00:04Z",
+		},
+		{
+			id:           "vid-ent-002",
+			channelID:    "UCfixtureShow0000000000",
+			categoryName: "Entertainment",
+			thumbnail:    "https://i.ytimg.com/vi/vid-ent-002/mqdefault.jpg",
+			views:        912334, likes: 41022,
+			seconds: 1452, format: model.FormatLong,
+			publishedAt: "2026-10-16T20:30:00Z",
+		},
+		{
+			id:           "vid-short-003",
+			channelID:    "UCfixtureShow0000000000",
+			categoryName: "Entertainment",
+			thumbnail:    "https://i.ytimg.com/vi/vid-short-003/default.jpg",
+			views:        503118, likes: 38100,
+			seconds: 45, format: model.FormatShort,
+			publishedAt: "2026-10-17T09:12:45Z",
+		},
+		{
+			id:           "vid-news-004",
+			channelID:    "UCfixtureNews0000000000",
+			categoryName: "News & Politics",
+			thumbnail:    "https://i.ytimg.com/vi/vid-news-004/mqdefault.jpg",
+			views:        120455, likes: 2210,
+			seconds: 602, format: model.FormatStandard,
+			publishedAt: "2026-10-17T06:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			video, ok := byID[tt.id]
+			if !ok {
+				t.Fatalf("video %s missing", tt.id)
+			}
+			publishedAt, _ := time.Parse(time.RFC3339, tt.publishedAt)
+
+			if video.ChannelID != tt.channelID {
+				t.Errorf("ChannelID = %q, want %q", video.ChannelID, tt.channelID)
+			}
+			if video.CategoryName != tt.categoryName {
+				t.Errorf("CategoryName = %q, want %q", video.CategoryName, tt.categoryName)
+			}
+			if video.Thumbnail != tt.thumbnail {
+				t.Errorf("Thumbnail = %q, want %q", video.Thumbnail, tt.thumbnail)
+			}
+			if video.ViewCount != tt.views || video.LikeCount != tt.likes {
+				t.Errorf("counts = %d/%d, want %d/%d", video.ViewCount, video.LikeCount, tt.views, tt.likes)
+			}
+			if video.DurationSeconds != tt.seconds || video.Format != tt.format {
+				t.Errorf("duration = %d %q, want %d %q", video.DurationSeconds, video.Format, tt.seconds, tt.format)
+			}
+			if !video.PublishedAt.Equal(publishedAt) {
+				t.Errorf("PublishedAt = %v, want %v", video.PublishedAt, publishedAt)
+			}
+			if video.VideoURL != "https://www.youtube.com/watch?v="+tt.id {
+				t.Errorf("VideoURL = %q", video.VideoURL)
+			}
+			if video.Region != "US" || video.Origin != model.OriginTrending {
+				t.Errorf("region/origin = %q/%q, want US/trending", video.Region, video.Origin)
+			}
+		})
+	}
+}
+
+func TestStoreVideos(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	tests := []struct {
+		name   string
+		origin string
+	}{
+		{"trending", model.OriginTrending},
+		{"search", model.OriginSearch},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			f := newTestFetcher(testConfig(), mt.DB)
+			videos := fixtureVideos(mt.T, f, "US", tt.origin)
+
+			// vid-music-001 gained 200000 views in the two hours since its last snapshot
+			previous := bson.D{
+				{Key: "_id", Value: "vid-music-001"},
+				{Key: "snapshot", Value: bson.D{
+					{Key: "videoId", Value: "vid-music-001"},
+					{Key: "viewCount", Value: int64(2641093)},
+					{Key: "recordedAt", Value: time.Now().Add(-2 * time.Hour)},
+				}},
+			}
+			upserted := bson.A{}
+			for i := range videos {
+				upserted = append(upserted, bson.D{{Key: "index", Value: i}, {Key: "_id", Value: primitive.NewObjectID()}})
+			}
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, "test."+historyCollection, mtest.FirstBatch, previous),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: len(videos)}),
+				mtest.CreateSuccessResponse(
+					bson.E{Key: "n", Value: len(videos)},
+					bson.E{Key: "nModified", Value: 0},
+					bson.E{Key: "upserted", Value: upserted},
+				),
+			)
+
+			stored, err := f.storeVideos(context.Background(), videos, "US", "")
+			if err != nil {
+				mt.Fatalf("storeVideos: %v", err)
+			}
+			if stored != len(videos) {
+				mt.Errorf("stored = %d, want %d", stored, len(videos))
+			}
+
+			nextCommand(mt, "aggregate")
+
+			var insert struct {
+				Documents []model.VideoStatsSnapshot `bson:"documents"`
+			}
+			if err := bson.Unmarshal(nextCommand(mt, "insert"), &insert); err != nil {
+				mt.Fatalf("decode insert: %v", err)
+			}
+			if len(insert.Documents) != len(videos) {
+				mt.Fatalf("got %d snapshots, want %d", len(insert.Documents), len(videos))
+			}
+			for _, snapshot := range insert.Documents {
+				if snapshot.Origin != tt.origin || snapshot.ChannelID == "" {
+					mt.Errorf("snapshot %s: origin %q channel %q", snapshot.VideoID, snapshot.Origin, snapshot.ChannelID)
+				}
+				if snapshot.VideoID == "vid-music-001" && math.Abs(snapshot.ViewVelocity-100000) > 1 {
+					mt.Errorf("velocity from previous snapshot = %v, want ~100000", snapshot.ViewVelocity)
+				}
+				if snapshot.ViewVelocity <= 0 {
+					mt.Errorf("snapshot %s: velocity %v", snapshot.VideoID, snapshot.ViewVelocity)
+				}
+			}
+
+			updates := nextUpdates(mt)
+			if len(updates) != len(videos) {
+				mt.Fatalf("got %d updates, want %d", len(updates), len(videos))
+			}
+			for i, update := range updates {
+				if !update.Upsert {
+					mt.Errorf("update %d is not an upsert", i)
+				}
+				if got := update.Q.Lookup("videoId").StringValue(); got != videos[i].VideoID {
+					mt.Errorf("update %d filters on %q, want %q", i, got, videos[i].VideoID)
+				}
+
+				set := update.U.Lookup("$set").Document()
+				_, hasRegion := set.Lookup("region").StringValueOK()
+				_, hasSetOnInsert := update.U.Lookup("$setOnInsert").DocumentOK()
+
+				switch tt.origin {
+				case model.OriginTrending:
+					if origin, _ := set.Lookup("origin").StringValueOK(); origin != model.OriginTrending {
+						mt.Errorf("update %d: $set.origin = %q", i, origin)
+					}
+					if availability, _ := set.Lookup("availability").StringValueOK(); availability != model.AvailabilityAvailable {
+						mt.Errorf("update %d: $set.availability = %q", i, availability)
+					}
+					if _, ok := update.U.Lookup("$unset").DocumentOK(); !ok {
+						mt.Errorf("update %d: missing $unset of availability fields", i)
+					}
+					if !hasRegion || hasSetOnInsert {
+						mt.Errorf("update %d: trending must claim the region", i)
+					}
+				case model.OriginSearch:
+					// Search must not demote a trending video or move its region
+					if hasRegion || !hasSetOnInsert {
+						mt.Errorf("update %d: search must only set region on insert", i)
+					}
+					if origin, _ := update.U.Lookup("$setOnInsert", "origin").StringValueOK(); origin != model.OriginSearch {
+						mt.Errorf("update %d: $setOnInsert.origin = %q", i, origin)
+					}
+					if _, ok := set.Lookup("availability").StringValueOK(); ok {
+						mt.Errorf("update %d: search results must not reset availability", i)
+					}
+				}
+			}
+		})
+	}
+}
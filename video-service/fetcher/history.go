@@ -0,0 +1,140 @@
+package fetcher
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+	"video-service/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const historyCollection = "video_stats_history"
+
+func (f *Fetcher) ensureHistoryIndexes(ctx context.Context) {
+	collection := f.db.Collection(historyCollection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "videoId", Value: 1},
+				{Key: "recordedAt", Value: -1},
+			},
+		},
+		{
+			// Trending channels
+			Keys: bson.D{
+				{Key: "recordedAt", Value: -1},
+				{Key: "channelId", Value: 1},
+			},
+		},
+		{
+			// Old snapshots expire on their own
+			Keys:    bson.D{{Key: "recordedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(f.config.HistoryRetention.Seconds())),
+		},
+	}
+
+	for _, index := range indexes {
+		_, err := collection.Indexes().CreateOne(ctx, index)
+		if err != nil {
+			log.Printf("Warning: Failed to create history index: %v", err)
+		}
+	}
+}
+
+// recordStats appends a snapshot per video to the history collection and
+// fills in ViewVelocity and LikeRatio on the videos. Velocity is measured
+// against the newest snapshot at least VelocityMinGap old, so a video seen in
+// several region/category fetches of the same run isn't compared with itself.
+// Videos without such a snapshot fall back to their lifetime average.
+func (f *Fetcher) recordStats(ctx context.Context, videos []model.Video) {
+	now := time.Now()
+
+	ids := make([]string, len(videos))
+	for i, video := range videos {
+		ids[i] = video.VideoID
+	}
+
+	previous, err := f.previousSnapshots(ctx, ids, now.Add(-f.config.VelocityMinGap))
+	if err != nil {
+		log.Printf("Failed to load previous stats snapshots: %v", err)
+	}
+
+	snapshots := make([]interface{}, 0, len(videos))
+	for i := range videos {
+		video := &videos[i]
+
+		if prev, ok := previous[video.VideoID]; ok {
+			video.ViewVelocity = viewVelocity(video.ViewCount-prev.ViewCount, now.Sub(prev.RecordedAt))
+		} else {
+			video.ViewVelocity = viewVelocity(video.ViewCount, now.Sub(video.PublishedAt))
+		}
+		if video.ViewCount > 0 {
+			video.LikeRatio = float64(video.LikeCount) / float64(video.ViewCount)
+		}
+
+		snapshots = append(snapshots, model.VideoStatsSnapshot{
+			VideoID:      video.VideoID,
+			ChannelID:    video.ChannelID,
+			Region:       video.Region,
+			Origin:       video.Origin,
+			ViewCount:    video.ViewCount,
+			LikeCount:    video.LikeCount,
+			ViewVelocity: video.ViewVelocity,
+			RecordedAt:   now,
+		})
+	}
+
+	if _, err := f.db.Collection(historyCollection).InsertMany(ctx, snapshots); err != nil {
+		log.Printf("Failed to append stats history: %v", err)
+	}
+}
+
+// previousSnapshots returns the newest snapshot recorded before the cutoff
+// for each video
+func (f *Fetcher) previousSnapshots(ctx context.Context, ids []string, before time.Time) (map[string]model.VideoStatsSnapshot, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"videoId":    bson.M{"$in": ids},
+			"recordedAt": bson.M{"$lte": before},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "videoId", Value: 1}, {Key: "recordedAt", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$videoId",
+			"snapshot": bson.M{"$first": "$$ROOT"},
+		}}},
+	}
+
+	cursor, err := f.db.Collection(historyCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Snapshot model.VideoStatsSnapshot `bson:"snapshot"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	previous := make(map[string]model.VideoStatsSnapshot, len(results))
+	for _, result := range results {
+		previous[result.Snapshot.VideoID] = result.Snapshot
+	}
+	return previous, nil
+}
+
+// viewVelocity returns views per hour, treating anything under an hour as an
+// hour so brand-new uploads don't get absurd rates
+func viewVelocity(views int64, elapsed time.Duration) float64 {
+	if views <= 0 {
+		return 0
+	}
+	hours := math.Max(elapsed.Hours(), 1)
+	return math.Round(float64(views)/hours*100) / 100
+}
@@ -0,0 +1,76 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+	"video-service/config"
+	"video-service/model"
+	"video-service/youtube"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// Tests run against the fake YouTube client and the mongo driver's mock
+// deployment, so they need neither an API key nor a database. The commands
+// the fetcher would send are checked instead of stored documents.
+
+// The recorded API responses live with the fake client
+const fixturesDir = "../youtube/testdata"
+
+func testConfig() *config.Config {
+	return &config.Config{
+		ShortMaxDuration:       time.Minute,
+		LongMinDuration:        20 * time.Minute,
+		VelocityMinGap:         30 * time.Minute,
+		CommentMaxPages:        3,
+		ChannelRefreshInterval: 24 * time.Hour,
+	}
+}
+
+// newTestFetcher skips NewFetcher so no index or backfill commands are sent
+func newTestFetcher(cfg *config.Config, db *mongo.Database) *Fetcher {
+	return &Fetcher{config: cfg, db: db, yt: youtube.NewFakeClient(fixturesDir)}
+}
+
+func fixtureVideos(t *testing.T, f *Fetcher, region, origin string) []model.Video {
+	t.Helper()
+	items, err := f.yt.Trending(context.Background(), region, "", 50)
+	if err != nil {
+		t.Fatalf("Trending: %v", err)
+	}
+	return f.toVideos(items, region, origin)
+}
+
+// writeOp is one statement of an update command; replacements arrive as u too
+type writeOp struct {
+	Q      bson.Raw `bson:"q"`
+	U      bson.Raw `bson:"u"`
+	Upsert bool     `bson:"upsert"`
+}
+
+// nextCommand returns the next command sent to the mock deployment
+func nextCommand(mt *mtest.T, name string) bson.Raw {
+	mt.Helper()
+	started := mt.GetStartedEvent()
+	if started == nil {
+		mt.Fatalf("expected %s command, got none", name)
+	}
+	if started.CommandName != name {
+		mt.Fatalf("expected %s command, got %s", name, started.CommandName)
+	}
+	return started.Command
+}
+
+func nextUpdates(mt *mtest.T) []writeOp {
+	mt.Helper()
+	var command struct {
+		Updates []writeOp `bson:"updates"`
+	}
+	if err := bson.Unmarshal(nextCommand(mt, "update"), &command); err != nil {
+		mt.Fatalf("decode update command: %v", err)
+	}
+	return command.Updates
+}
@@ -0,0 +1,36 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"video-service/model"
+)
+
+// SearchAndStore runs a YouTube search, loads full details for the hits and
+// stores them so the same query can be answered locally next time. duration
+// is YouTube's videoDuration bucket (short, medium, long) or empty.
+// A search costs 100 quota units plus 1 for the details.
+func (f *Fetcher) SearchAndStore(ctx context.Context, query, region string, maxResults int, duration string) ([]model.Video, error) {
+	ids, err := f.yt.Search(ctx, query, region, maxResults, duration)
+	if err != nil {
+		return nil, fmt.Errorf("YouTube search failed: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	items, err := f.yt.Videos(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("YouTube video details failed: %w", err)
+	}
+
+	videos := f.toVideos(items, region, model.OriginSearch)
+	if _, err := f.storeVideos(ctx, videos, region, ""); err != nil {
+		// The results are still good for this response
+		log.Printf("Failed to store search results for query=%q: %v", query, err)
+	}
+
+	log.Printf("YouTube search for query=%q region=%s returned %d videos", query, region, len(videos))
+	return videos, nil
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"video-service/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetChannel returns a stored channel
+func GetChannel(c *gin.Context) {
+	channelID := c.Param("id")
+	log.Printf("[INFO] GetChannel called with channelId: %s", channelID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var channel model.Channel
+	err := db.Collection("channels").FindOne(ctx, bson.M{"_id": channelID}).Decode(&channel)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "channel not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("[ERROR] Database query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+// GetChannelVideos returns a channel's stored videos, newest first
+func GetChannelVideos(c *gin.Context) {
+	channelID := c.Param("id")
+	maxResultsStr := c.DefaultQuery("maxResults", "20")
+	pageStr := c.DefaultQuery("page", "1")
+
+	log.Printf("[INFO] GetChannelVideos called with channelId: %s, maxResults: %s, page: %s", channelID, maxResultsStr, pageStr)
+
+	maxResults, err := strconv.Atoi(maxResultsStr)
+	if err != nil || maxResults <= 0 || maxResults > 50 {
+		log.Printf("[WARN] Invalid maxResults: %s", maxResultsStr)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maxResults, must be between 1 and 50"})
+		return
+	}
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	filter := bson.M{"channelId": channelID, "availability": bson.M{"$ne": model.AvailabilityUnavailable}}
+	if err := applyDurationFilters(c, filter); err != nil {
+		log.Printf("[WARN] Invalid duration filter: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "publishedAt", Value: -1}}).
+		SetLimit(int64(maxResults)).
+		SetSkip(int64((page - 1) * maxResults))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := db.Collection("videos").Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("[ERROR] Database query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var videos []model.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		log.Printf("[ERROR] Failed to decode videos: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode videos"})
+		return
+	}
+
+	log.Printf("[INFO] Retrieved %d videos for channelId=%s", len(videos), channelID)
+	renderVideos(c, videos, nil)
+}
+
+// GetTrendingChannels ranks channels by how many of their videos trended
+// across regions within the window. A video trending in three regions counts
+// three times, so channels with broad reach beat ones that only chart at home.
+func GetTrendingChannels(c *gin.Context) {
+	region := c.Query("region")
+	hoursStr := c.DefaultQuery("hours", "48")
+	maxResultsStr := c.DefaultQuery("maxResults", "20")
+
+	log.Printf("[INFO] GetTrendingChannels called with region: %s, hours: %s, maxResults: %s", region, hoursStr, maxResultsStr)
+
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil || hours <= 0 || hours > 24*30 {
+		log.Printf("[WARN] Invalid hours: %s", hoursStr)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hours, must be between 1 and 720"})
+		return
+	}
+
+	maxResults, err := strconv.Atoi(maxResultsStr)
+	if err != nil || maxResults <= 0 || maxResults > 50 {
+		log.Printf("[WARN] Invalid maxResults: %s", maxResultsStr)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maxResults, must be between 1 and 50"})
+		return
+	}
+
+	match := bson.M{
+		"recordedAt": bson.M{"$gte": time.Now().Add(-time.Duration(hours) * time.Hour)},
+		"channelId":  bson.M{"$nin": []interface{}{nil, ""}},
+		"origin":     bson.M{"$ne": model.OriginSearch},
+	}
+	if region != "" {
+		match["region"] = region
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		// One row per (channel, video, region) placement
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"channel": "$channelId", "video": "$videoId", "region": "$region"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$_id.channel",
+			"placements": bson.M{"$sum": 1},
+			"videos":     bson.M{"$addToSet": "$_id.video"},
+			"regions":    bson.M{"$addToSet": "$_id.region"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "placements", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "channels",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "channel",
+		}}},
+		// Channels not stored yet drop out here, so limit afterwards
+		{{Key: "$unwind", Value: "$channel"}},
+		{{Key: "$limit", Value: maxResults}},
+		{{Key: "$project", Value: bson.M{
+			"channel":        1,
+			"placements":     1,
+			"regions":        1,
+			"trendingVideos": bson.M{"$size": "$videos"},
+		}}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := db.Collection("video_stats_history").Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("[ERROR] Database aggregation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	channels := []model.TrendingChannel{}
+	if err := cursor.All(ctx, &channels); err != nil {
+		log.Printf("[ERROR] Failed to decode trending channels: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode channels"})
+		return
+	}
+
+	log.Printf("[INFO] Retrieved %d trending channels", len(channels))
+	c.JSON(http.StatusOK, channels)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"video-service/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// commentFetches holds videos whose comments were fetched on demand, so a
+// video without comments doesn't cost a quota unit on every request
+var commentFetches = &recentCache{seen: make(map[string]time.Time)}
+
+const commentFetchTTL = time.Hour
+
+// commentCursor is the keyset position after the last thread of a page
+type commentCursor struct {
+	Relevance int       `json:"r,omitempty"`
+	Time      time.Time `json:"t,omitempty"`
+	ID        string    `json:"id"`
+}
+
+func encodeCommentCursor(cursor commentCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCommentCursor(value string) (commentCursor, error) {
+	var cursor commentCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.ID == "" {
+		return cursor, errors.New("cursor has no id")
+	}
+	return cursor, nil
+}
+
+// GetComments serves stored comment threads for a video. Comments of trending
+// videos are refreshed by the worker; other videos get one page fetched the
+// first time they're asked for.
+func GetComments(c *gin.Context) {
+	videoID := c.Query("videoId")
+	maxResultsStr := c.DefaultQuery("maxResults", "20")
+	sortBy := c.DefaultQuery("sort", "relevance")
+
+	log.Printf("[INFO] GetComments called with videoId: %s, maxResults: %s, sort: %s", videoID, maxResultsStr, sortBy)
+
+	if videoID == "" {
+		log.Printf("[WARN] Missing videoId")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "videoId is required"})
+		return
+	}
+
+	maxResults, err := strconv.Atoi(maxResultsStr)
+	if err != nil || maxResults <= 0 || maxResults > 100 {
+		log.Printf("[WARN] Invalid maxResults: %s", maxResultsStr)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maxResults, must be between 1 and 100"})
+		return
+	}
+
+	if sortBy != "relevance" && sortBy != "time" {
+		log.Printf("[WARN] Invalid sort: %s", sortBy)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be relevance or time"})
+		return
+	}
+
+	var after *commentCursor
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := decodeCommentCursor(value)
+		if err != nil {
+			log.Printf("[WARN] Invalid cursor: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		after = &cursor
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	unavailable, err := db.Collection("videos").CountDocuments(ctx,
+		bson.M{"videoId": videoID, "availability": model.AvailabilityUnavailable}, options.Count().SetLimit(1))
+	if err != nil {
+		log.Printf("[ERROR] Database query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+	if unavailable > 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "video is no longer available"})
+		return
+	}
+
+	collection := db.Collection("comments")
+	threadFilter := bson.M{"videoId": videoID, "parentId": ""}
+
+	if after == nil && videoFetcher != nil && !commentFetches.recent(videoID, commentFetchTTL) {
+		count, err := collection.CountDocuments(ctx, threadFilter, options.Count().SetLimit(1))
+		if err == nil && count == 0 {
+			commentFetches.mark(videoID, commentFetchTTL)
+			if _, err := videoFetcher.RefreshComments(ctx, videoID, 1); err != nil {
+				log.Printf("[ERROR] On-demand comment fetch failed: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+				return
+			}
+		}
+	}
+
+	filter := threadFilter
+	sort := bson.D{{Key: "relevance", Value: 1}, {Key: "_id", Value: 1}}
+	if sortBy == "time" {
+		sort = bson.D{{Key: "publishedAt", Value: -1}, {Key: "_id", Value: 1}}
+	}
+	if after != nil {
+		if sortBy == "time" {
+			filter["$or"] = []bson.M{
+				{"publishedAt": bson.M{"$lt": after.Time}},
+				{"publishedAt": after.Time, "_id": bson.M{"$gt": after.ID}},
+			}
+		} else {
+			filter["$or"] = []bson.M{
+				{"relevance": bson.M{"$gt": after.Relevance}},
+				{"relevance": after.Relevance, "_id": bson.M{"$gt": after.ID}},
+			}
+		}
+	}
+
+	// One extra row tells us whether there's a next page
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(sort).SetLimit(int64(maxResults+1)))
+	if err != nil {
+		log.Printf("[ERROR] Database query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var threads []model.VideoComment
+	if err := cursor.All(ctx, &threads); err != nil {
+		log.Printf("[ERROR] Failed to decode comments: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode comments"})
+		return
+	}
+
+	response := model.CommentListResponse{VideoID: videoID, Sort: sortBy, Comments: []model.CommentThreadView{}}
+	if len(threads) > maxResults {
+		threads = threads[:maxResults]
+		last := threads[len(threads)-1]
+		response.NextCursor = encodeCommentCursor(commentCursor{Relevance: last.Relevance, Time: last.PublishedAt, ID: last.ID})
+	}
+
+	replies, err := loadReplies(ctx, videoID, threads)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load replies: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+
+	for _, thread := range threads {
+		view := model.CommentThreadView{VideoComment: thread, Replies: replies[thread.ID]}
+		if view.Replies == nil {
+			view.Replies = []model.VideoComment{}
+		}
+		response.Comments = append(response.Comments, view)
+	}
+	response.Count = len(response.Comments)
+
+	log.Printf("[INFO] Retrieved %d comment threads for videoId=%s", response.Count, videoID)
+	c.JSON(http.StatusOK, response)
+}
+
+// loadReplies returns the stored replies of the given threads, oldest first
+func loadReplies(ctx context.Context, videoID string, threads []model.VideoComment) (map[string][]model.VideoComment, error) {
+	if len(threads) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(threads))
+	for i, thread := range threads {
+		ids[i] = thread.ID
+	}
+
+	cursor, err := db.Collection("comments").Find(ctx,
+		bson.M{"videoId": videoID, "parentId": bson.M{"$in": ids}},
+		options.Find().SetSort(bson.D{{Key: "publishedAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []model.VideoComment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	replies := make(map[string][]model.VideoComment)
+	for _, comment := range comments {
+		replies[comment.ParentID] = append(replies[comment.ParentID], comment)
+	}
+	return replies, nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"video-service/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var videoFormats = map[string]bool{
+	model.FormatShort:    true,
+	model.FormatStandard: true,
+	model.FormatLong:     true,
+}
+
+// applyDurationFilters adds the minDuration/maxDuration (seconds) and format
+// query parameters to a videos filter. format takes a comma-separated list,
+// e.g. format=short for the vertical-scroll feed; see parseFormat.
+func applyDurationFilters(c *gin.Context, filter bson.M) error {
+	duration := bson.M{}
+	if value := c.Query("minDuration"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("invalid minDuration, must be a number of seconds")
+		}
+		duration["$gte"] = seconds
+	}
+	if value := c.Query("maxDuration"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("invalid maxDuration, must be a number of seconds")
+		}
+		duration["$lte"] = seconds
+	}
+	if min, ok := duration["$gte"].(int64); ok {
+		if max, ok := duration["$lte"].(int64); ok && max < min {
+			return fmt.Errorf("maxDuration must not be less than minDuration")
+		}
+	}
+	if len(duration) > 0 {
+		filter["durationSeconds"] = duration
+	}
+
+	_, formats, err := parseFormat(c.Query("format"))
+	if err != nil {
+		return err
+	}
+	if len(formats) > 0 {
+		filter["format"] = bson.M{"$in": formats}
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"video-service/matrix"
 	"video-service/model"
 	"video-service/service"
 
@@ -21,38 +23,36 @@ func InitDB(database *mongo.Database) {
 	db = database
 }
 
+var videoService *service.Service
+
+// InitService sets the service behind GetVideoStats
+func InitService(s *service.Service) {
+	videoService = s
+}
+
+var fetchMatrix *matrix.Store
+
+// InitMatrix sets the fetch matrix behind GetRegions and GetCategories
+func InitMatrix(m *matrix.Store) {
+	fetchMatrix = m
+}
+
+// GetCategories lists the categories fetched for a region
 func GetCategories(c *gin.Context) {
 	region := c.DefaultQuery("region", "US")
 	log.Printf("[INFO] GetCategories called with region: %s", region)
 
-	// For simplicity, return predefined categories
-	categories := []model.CategoryResponse{
-		{ID: "10", Title: "Music"},
-		{ID: "24", Title: "Entertainment"},
-		{ID: "25", Title: "News & Politics"},
-		{ID: "17", Title: "Sports"},
-		{ID: "20", Title: "Gaming"},
-		{ID: "23", Title: "Comedy"},
-		{ID: "26", Title: "Howto & Style"},
-		{ID: "27", Title: "Education"},
-		{ID: "28", Title: "Science & Technology"},
-	}
+	categories := fetchMatrix.Categories(strings.ToUpper(region))
 
 	log.Printf("[INFO] Retrieved %d categories for region %s", len(categories), region)
 	c.JSON(http.StatusOK, categories)
 }
 
+// GetRegions lists the regions in the fetch matrix
 func GetRegions(c *gin.Context) {
 	log.Printf("[INFO] GetRegions called")
 
-	// Return supported regions with names
-	regions := []model.RegionResponse{
-		{Code: "US", Name: "United States"},
-		{Code: "IN", Name: "India"},
-		{Code: "DE", Name: "Germany"},
-		{Code: "GB", Name: "United Kingdom"},
-		{Code: "CA", Name: "Canada"},
-	}
+	regions := fetchMatrix.Regions()
 
 	log.Printf("[INFO] Retrieved %d regions", len(regions))
 	c.JSON(http.StatusOK, regions)
@@ -85,11 +85,21 @@ func GetVideos(c *gin.Context) {
 		page = 1
 	}
 
-	// Build filter
-	filter := bson.M{"region": region}
+	// Build filter; videos stored from search fallbacks or found unavailable
+	// aren't part of the listings
+	filter := bson.M{
+		"region":       region,
+		"origin":       bson.M{"$ne": model.OriginSearch},
+		"availability": bson.M{"$ne": model.AvailabilityUnavailable},
+	}
 	if category != "" && category != "0" {
 		filter["categoryId"] = category
 	}
+	if err := applyDurationFilters(c, filter); err != nil {
+		log.Printf("[WARN] Invalid duration filter: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Calculate skip for pagination
 	skip := (page - 1) * maxResults
@@ -120,46 +130,34 @@ func GetVideos(c *gin.Context) {
 		return
 	}
 
-	// Transform videos to YouTube API format for frontend compatibility
-	transformedVideos := make([]map[string]interface{}, len(videos))
-	for i, video := range videos {
-		transformedVideos[i] = map[string]interface{}{
-			"id": map[string]interface{}{
-				"videoId": video.VideoID,
-			},
-			"snippet": map[string]interface{}{
-				"title":        video.Title,
-				"description":  video.Description,
-				"channelTitle": video.ChannelTitle,
-				"publishedAt":  video.PublishedAt.Format(time.RFC3339),
-				"thumbnails": map[string]interface{}{
-					"medium": map[string]interface{}{
-						"url": video.Thumbnail,
-					},
-					"default": map[string]interface{}{
-						"url": video.Thumbnail,
-					},
-				},
-				"categoryId": video.CategoryID,
-			},
-			"videoURL":     video.VideoURL,
-			"viewCount":    video.ViewCount,
-			"likeCount":    video.LikeCount,
-			"duration":     video.Duration,
-			"region":       video.Region,
-			"categoryName": video.CategoryName,
-		}
-	}
+	videos, explanations := personalizeVideos(c, videos)
 
 	log.Printf("[INFO] Retrieved %d videos for region=%s, category=%s", len(videos), region, category)
-	c.JSON(http.StatusOK, transformedVideos)
+	renderVideos(c, videos, explanations)
+}
+
+// trendingSorts maps the sort parameter of /api/trending to its sort order.
+// views is the default; velocity is opt-in and favours videos gaining views
+// now over old hits with large totals.
+var trendingSorts = map[string]bson.D{
+	"velocity": {{Key: "viewVelocity", Value: -1}, {Key: "viewCount", Value: -1}},
+	"views":    {{Key: "viewCount", Value: -1}},
+	"recent":   {{Key: "publishedAt", Value: -1}},
 }
 
 func GetTrending(c *gin.Context) {
 	maxResultsStr := c.DefaultQuery("maxResults", "20")
 	region := c.DefaultQuery("region", "US")
+	sortBy := c.DefaultQuery("sort", "views")
 
-	log.Printf("[INFO] GetTrending called with maxResults=%s, region=%s", maxResultsStr, region)
+	log.Printf("[INFO] GetTrending called with maxResults=%s, region=%s, sort=%s", maxResultsStr, region, sortBy)
+
+	sortFields, ok := trendingSorts[sortBy]
+	if !ok {
+		log.Printf("[WARN] Invalid sort: %s", sortBy)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of velocity, views, recent"})
+		return
+	}
 
 	maxResults, err := strconv.Atoi(maxResultsStr)
 	if err != nil || maxResults <= 0 || maxResults > 50 {
@@ -168,10 +166,18 @@ func GetTrending(c *gin.Context) {
 		return
 	}
 
-	// Get trending videos (sorted by view count)
-	filter := bson.M{"region": region}
+	filter := bson.M{
+		"region":       region,
+		"origin":       bson.M{"$ne": model.OriginSearch},
+		"availability": bson.M{"$ne": model.AvailabilityUnavailable},
+	}
+	if err := applyDurationFilters(c, filter); err != nil {
+		log.Printf("[WARN] Invalid duration filter: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	opts := options.Find().
-		SetSort(bson.D{{Key: "viewCount", Value: -1}}).
+		SetSort(sortFields).
 		SetLimit(int64(maxResults))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -193,102 +199,13 @@ func GetTrending(c *gin.Context) {
 		return
 	}
 
-	// Transform videos to YouTube API format for frontend compatibility
-	transformedVideos := make([]map[string]interface{}, len(videos))
-	for i, video := range videos {
-		transformedVideos[i] = map[string]interface{}{
-			"id": map[string]interface{}{
-				"videoId": video.VideoID,
-			},
-			"snippet": map[string]interface{}{
-				"title":        video.Title,
-				"description":  video.Description,
-				"channelTitle": video.ChannelTitle,
-				"publishedAt":  video.PublishedAt.Format(time.RFC3339),
-				"thumbnails": map[string]interface{}{
-					"medium": map[string]interface{}{
-						"url": video.Thumbnail,
-					},
-					"default": map[string]interface{}{
-						"url": video.Thumbnail,
-					},
-				},
-				"categoryId": video.CategoryID,
-			},
-			"videoURL":     video.VideoURL,
-			"viewCount":    video.ViewCount,
-			"likeCount":    video.LikeCount,
-			"duration":     video.Duration,
-			"region":       video.Region,
-			"categoryName": video.CategoryName,
-		}
-	}
+	videos, explanations := personalizeVideos(c, videos)
 
 	log.Printf("[INFO] Retrieved %d trending videos for region=%s", len(videos), region)
-	c.JSON(http.StatusOK, transformedVideos)
-}
-
-func SearchVideos(c *gin.Context) {
-	query := c.Query("query")
-	region := c.DefaultQuery("region", "US")
-	maxResultsStr := c.DefaultQuery("maxResults", "10")
-
-	log.Printf("[INFO] SearchVideos called with query: %s, region: %s, maxResults: %s", query, region, maxResultsStr)
-
-	if query == "" {
-		log.Printf("[WARN] Missing query parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'query' is required"})
-		return
-	}
-
-	maxResults, err := strconv.Atoi(maxResultsStr)
-	if err != nil || maxResults <= 0 || maxResults > 50 {
-		maxResults = 10
-	}
-
-	// Use YouTube API directly for search
-	data, err := service.SearchYouTubeVideos(query, region, maxResults)
-	if err != nil {
-		log.Printf("[ERROR] YouTube search failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
-		return
-	}
-
-	log.Printf("[INFO] YouTube search completed for query='%s', region=%s", query, region)
-	c.JSON(http.StatusOK, data)
-}
-
-// Legacy handlers that still use YouTube API directly for compatibility
-func GetComments(c *gin.Context) {
-	videoID := c.Query("videoId")
-	maxResultsStr := c.DefaultQuery("maxResults", "10")
-
-	log.Printf("[INFO] GetComments called with videoId: %s, maxResults: %s", videoID, maxResultsStr)
-
-	if videoID == "" {
-		log.Printf("[WARN] Missing videoId")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "videoId is required"})
-		return
-	}
-
-	maxResults, err := strconv.Atoi(maxResultsStr)
-	if err != nil || maxResults <= 0 || maxResults > 100 {
-		log.Printf("[WARN] Invalid maxResults: %s", maxResultsStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maxResults, must be between 1 and 100"})
-		return
-	}
-
-	data, err := service.FetchComments(videoID, maxResults)
-	if err != nil {
-		log.Printf("[ERROR] FetchComments failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	log.Printf("[INFO] Retrieved comments for videoId=%s", videoID)
-	c.JSON(http.StatusOK, data)
+	renderVideos(c, videos, explanations)
 }
 
+// Legacy handler that still uses the YouTube API directly for compatibility
 func GetVideoStats(c *gin.Context) {
 	videoID := c.Query("videoId")
 	log.Printf("[INFO] GetVideoStats called with videoId: %s", videoID)
@@ -299,7 +216,10 @@ func GetVideoStats(c *gin.Context) {
 		return
 	}
 
-	data, err := service.FetchVideoStatistics(videoID)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	data, err := videoService.FetchVideoStatistics(ctx, videoID)
 	if err != nil {
 		log.Printf("[ERROR] FetchVideoStatistics failed: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
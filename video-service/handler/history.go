@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"video-service/model"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxHistoryPoints bounds a history response; a video fetched every 6h in a
+// few categories stays well under it for the default window
+const maxHistoryPoints = 1000
+
+// GetVideoHistory returns a video's view/like snapshots, oldest first, for charts
+func GetVideoHistory(c *gin.Context) {
+	videoID := c.Param("id")
+	hoursStr := c.DefaultQuery("hours", "168")
+
+	log.Printf("[INFO] GetVideoHistory called with videoId: %s, hours: %s", videoID, hoursStr)
+
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil || hours <= 0 || hours > 24*90 {
+		log.Printf("[WARN] Invalid hours: %s", hoursStr)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hours, must be between 1 and 2160"})
+		return
+	}
+
+	filter := bson.M{
+		"videoId":    videoID,
+		"recordedAt": bson.M{"$gte": time.Now().Add(-time.Duration(hours) * time.Hour)},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "recordedAt", Value: 1}}).
+		SetLimit(maxHistoryPoints).
+		SetProjection(bson.M{"_id": 0})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := db.Collection("video_stats_history").Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("[ERROR] Database query failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database query failed"})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	points := []model.VideoStatsSnapshot{}
+	if err := cursor.All(ctx, &points); err != nil {
+		log.Printf("[ERROR] Failed to decode history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode history"})
+		return
+	}
+
+	if len(points) == 0 {
+		count, err := db.Collection("videos").CountDocuments(ctx, bson.M{"videoId": videoID})
+		if err == nil && count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "video not found"})
+			return
+		}
+	}
+
+	log.Printf("[INFO] Retrieved %d history points for videoId=%s", len(points), videoID)
+	c.JSON(http.StatusOK, model.VideoHistoryResponse{
+		VideoID: videoID,
+		Points:  points,
+		Count:   len(points),
+	})
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+	"video-service/model"
+	"video-service/personalize"
+
+	"github.com/gin-gonic/gin"
+)
+
+var personalizer = personalize.NewClient()
+
+// personalizeVideos re-ranks videos for the caller's session. The returned map
+// holds per-video explanations when the request asked for explain=true.
+// Any failure leaves the original order untouched.
+func personalizeVideos(c *gin.Context, videos []model.Video) ([]model.Video, map[string]personalize.Ranking) {
+	sessionID := personalizer.SessionFor(c)
+	if sessionID == "" || len(videos) < 2 {
+		return videos, nil
+	}
+
+	byID := make(map[string]model.Video, len(videos))
+	candidates := make([]personalize.Candidate, 0, len(videos))
+	for _, video := range videos {
+		byID[video.VideoID] = video
+		candidates = append(candidates, personalize.Candidate{
+			ID:          video.VideoID,
+			Title:       video.Title,
+			Region:      video.Region,
+			Source:      video.ChannelTitle,
+			Category:    video.CategoryName,
+			PublishedAt: video.PublishedAt,
+			Popularity:  viewScore(video.ViewCount),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second)
+	defer cancel()
+
+	rankings, personalized, err := personalizer.Rank(ctx, sessionID, candidates)
+	if err != nil {
+		log.Printf("[WARN] Personalisation skipped: %v", err)
+		return videos, nil
+	}
+	if len(rankings) != len(videos) {
+		return videos, nil
+	}
+
+	ranked := make([]model.Video, 0, len(videos))
+	explanations := make(map[string]personalize.Ranking, len(rankings))
+	for _, ranking := range rankings {
+		video, ok := byID[ranking.ID]
+		if !ok {
+			return videos, nil
+		}
+		ranked = append(ranked, video)
+		explanations[ranking.ID] = ranking
+	}
+
+	log.Printf("[INFO] Personalised %d videos for session (history=%t)", len(ranked), personalized)
+	if c.Query("explain") != "true" {
+		explanations = nil
+	}
+	return ranked, explanations
+}
+
+// viewScore maps a view count onto 0..1 where 100M views saturates
+func viewScore(views int64) float64 {
+	if views <= 0 {
+		return 0
+	}
+	return math.Min(1, math.Log10(float64(views)+1)/8)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"video-service/model"
+	"video-service/personalize"
+	"video-service/schema"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseFormat splits the format parameter into the response shape (native or
+// youtube, default youtube) and the short/standard/long video formats to
+// filter on. The two value sets don't overlap, so format=native,short works.
+func parseFormat(value string) (string, []string, error) {
+	shape := ""
+	var formats []string
+	for _, token := range strings.Split(strings.ToLower(value), ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "":
+		case token == schema.ShapeNative || token == schema.ShapeYouTube:
+			if shape != "" && shape != token {
+				return "", nil, fmt.Errorf("format can't be both native and youtube")
+			}
+			shape = token
+		case videoFormats[token]:
+			formats = append(formats, token)
+		default:
+			return "", nil, fmt.Errorf("invalid format %q, must be native, youtube, short, standard or long", token)
+		}
+	}
+	if shape == "" {
+		shape = schema.ShapeYouTube
+	}
+	return shape, formats, nil
+}
+
+// responseShape returns the shape requested with format
+func responseShape(c *gin.Context) string {
+	shape, _, err := parseFormat(c.Query("format"))
+	if err != nil {
+		return schema.ShapeYouTube
+	}
+	return shape
+}
+
+// renderVideos writes a video listing in the requested shape: a bare array of
+// YouTube-style items, or a schema.VideoList
+func renderVideos(c *gin.Context, videos []model.Video, explanations map[string]personalize.Ranking) {
+	if responseShape(c) == schema.ShapeNative {
+		list := schema.VideoList{Videos: make([]schema.Video, len(videos)), Count: len(videos)}
+		for i, video := range videos {
+			list.Videos[i] = schema.NewVideo(video)
+			list.Videos[i].Personalization = personalization(explanations, video.VideoID)
+		}
+		c.JSON(http.StatusOK, list)
+		return
+	}
+
+	c.JSON(http.StatusOK, youtubeItems(videos, explanations))
+}
+
+func youtubeItems(videos []model.Video, explanations map[string]personalize.Ranking) []schema.YouTubeItem {
+	items := make([]schema.YouTubeItem, len(videos))
+	for i, video := range videos {
+		items[i] = schema.NewYouTubeItem(video)
+		items[i].Personalization = personalization(explanations, video.VideoID)
+	}
+	return items
+}
+
+func personalization(explanations map[string]personalize.Ranking, videoID string) *schema.Personalization {
+	ranking, ok := explanations[videoID]
+	if !ok {
+		return nil
+	}
+	return &schema.Personalization{Score: ranking.Score, Explanation: ranking.Explanation}
+}
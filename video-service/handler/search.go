@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"video-service/config"
+	"video-service/fetcher"
+	"video-service/model"
+	"video-service/schema"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	videoFetcher *fetcher.Fetcher
+	searchConfig *config.Config
+	fallbacks    = &recentCache{seen: make(map[string]time.Time)}
+)
+
+// InitSearch wires up the YouTube fallback for SearchVideos. Without it
+// search only looks at stored videos.
+func InitSearch(f *fetcher.Fetcher, cfg *config.Config) {
+	videoFetcher = f
+	searchConfig = cfg
+}
+
+// recentCache remembers keys seen within a TTL. Search uses it for queries
+// that already went to YouTube: their results are stored locally by then, so
+// asking again would only spend quota on the same answer.
+type recentCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func (c *recentCache) recent(key string, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	at, ok := c.seen[key]
+	return ok && time.Since(at) < ttl
+}
+
+func (c *recentCache) mark(key string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, at := range c.seen {
+		if time.Since(at) >= ttl {
+			delete(c.seen, k)
+		}
+	}
+	c.seen[key] = time.Now()
+}
+
+// durationBuckets maps YouTube's videoDuration values to durationSeconds
+// ranges: short is under 4 minutes, long is over 20
+var durationBuckets = map[string]bson.M{
+	"short":  {"$gt": 0, "$lt": 240},
+	"medium": {"$gte": 240, "$lte": 1200},
+	"long":   {"$gt": 1200},
+}
+
+// SearchVideos searches stored videos first and only asks YouTube when there
+// are fewer than SEARCH_MIN_LOCAL_RESULTS local hits
+func SearchVideos(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("query"))
+	region := c.DefaultQuery("region", "US")
+	category := c.Query("category")
+	duration := strings.ToLower(c.Query("duration"))
+	maxResultsStr := c.DefaultQuery("maxResults", "10")
+
+	log.Printf("[INFO] SearchVideos called with query: %s, region: %s, category: %s, duration: %s, maxResults: %s",
+		query, region, category, duration, maxResultsStr)
+
+	if query == "" {
+		log.Printf("[WARN] Missing query parameter")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'query' is required"})
+		return
+	}
+
+	maxResults, err := strconv.Atoi(maxResultsStr)
+	if err != nil || maxResults <= 0 || maxResults > 50 {
+		maxResults = 10
+	}
+
+	_, formats, err := parseFormat(c.Query("format"))
+	if err != nil {
+		log.Printf("[WARN] Invalid format: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if duration == "any" {
+		duration = ""
+	}
+	if _, ok := durationBuckets[duration]; duration != "" && !ok {
+		log.Printf("[WARN] Invalid duration: %s", duration)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be one of any, short, medium, long"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	videos, err := searchLocalVideos(ctx, query, region, category, duration, formats, maxResults)
+	if err != nil {
+		log.Printf("[ERROR] Local search failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	source := "local"
+	if needsFallback(len(videos), maxResults) {
+		// Category and formats narrow what is kept of the YouTube results, so a
+		// query under another filter may still need its own fallback
+		key := strings.Join([]string{strings.ToLower(query), region, duration, category, strings.Join(formats, ",")}, "|")
+		if !fallbacks.recent(key, searchConfig.SearchFallbackTTL) {
+			remote, err := videoFetcher.SearchAndStore(ctx, query, region, maxResults, duration)
+			if err != nil {
+				// Local results, however few, beat an error
+				log.Printf("[ERROR] YouTube search fallback failed: %v", err)
+			} else {
+				fallbacks.mark(key, searchConfig.SearchFallbackTTL)
+				videos = mergeSearchResults(videos, remote, category, formats, maxResults)
+				source = "youtube"
+			}
+		}
+	}
+
+	log.Printf("[INFO] Search completed for query='%s', region=%s: %d results (source=%s)", query, region, len(videos), source)
+	response := gin.H{
+		"count":  len(videos),
+		"query":  query,
+		"region": region,
+		"source": source,
+	}
+	if responseShape(c) == schema.ShapeNative {
+		native := make([]schema.Video, len(videos))
+		for i, video := range videos {
+			native[i] = schema.NewVideo(video)
+		}
+		response["videos"] = native
+	} else {
+		response["items"] = youtubeItems(videos, nil)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// searchLocalVideos runs a text search over stored videos
+func searchLocalVideos(ctx context.Context, query, region, category, duration string, formats []string, maxResults int) ([]model.Video, error) {
+	filter := bson.M{
+		"$text":        bson.M{"$search": query},
+		"region":       region,
+		"availability": bson.M{"$ne": model.AvailabilityUnavailable},
+	}
+	if category != "" && category != "0" {
+		filter["categoryId"] = category
+	}
+	if duration != "" {
+		filter["durationSeconds"] = durationBuckets[duration]
+	}
+	if len(formats) > 0 {
+		filter["format"] = bson.M{"$in": formats}
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{
+			{Key: "score", Value: bson.M{"$meta": "textScore"}},
+			{Key: "viewCount", Value: -1},
+		}).
+		SetLimit(int64(maxResults))
+
+	cursor, err := db.Collection("videos").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var videos []model.Video
+	if err := cursor.All(ctx, &videos); err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+func needsFallback(localCount, maxResults int) bool {
+	if videoFetcher == nil || searchConfig == nil || !searchConfig.SearchFallback {
+		return false
+	}
+	return localCount < min(searchConfig.SearchMinLocalResults, maxResults)
+}
+
+// mergeSearchResults appends YouTube results after the local ones, skipping
+// duplicates and videos outside the requested category or formats
+func mergeSearchResults(local, remote []model.Video, category string, formats []string, maxResults int) []model.Video {
+	seen := make(map[string]bool, len(local))
+	for _, video := range local {
+		seen[video.VideoID] = true
+	}
+
+	merged := local
+	for _, video := range remote {
+		if len(merged) >= maxResults {
+			break
+		}
+		if seen[video.VideoID] || (category != "" && category != "0" && video.CategoryID != category) {
+			continue
+		}
+		if len(formats) > 0 && !slices.Contains(formats, video.Format) {
+			continue
+		}
+		seen[video.VideoID] = true
+		merged = append(merged, video)
+	}
+	return merged
+}
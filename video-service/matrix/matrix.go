@@ -0,0 +1,256 @@
+// Package matrix holds the video fetch matrix: which region and category
+// pairs are fetched, how many videos each, and how often. It is the single
+// source of truth for the scheduler and the regions/categories endpoints, and
+// reloads from FETCH_MATRIX_FILE without a restart.
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"video-service/config"
+	"video-service/model"
+	"video-service/utils"
+)
+
+// Entry is one region/category pair to fetch
+type Entry struct {
+	Region    string
+	Category  string
+	MaxVideos int
+	Interval  time.Duration
+}
+
+// Key identifies an entry across reloads
+func (e Entry) Key() string {
+	return e.Region + "-" + e.Category
+}
+
+// fileEntry is the on-disk form; intervals are Go duration strings
+type fileEntry struct {
+	Region    string `json:"region"`
+	Category  string `json:"category"`
+	MaxVideos int    `json:"maxVideos"`
+	Interval  string `json:"interval"`
+}
+
+// regionNames covers the regions YouTube's mostPopular chart is useful for;
+// unknown codes are shown as-is
+var regionNames = map[string]string{
+	"US": "United States",
+	"IN": "India",
+	"DE": "Germany",
+	"GB": "United Kingdom",
+	"CA": "Canada",
+	"AU": "Australia",
+	"FR": "France",
+	"JP": "Japan",
+	"BR": "Brazil",
+	"MX": "Mexico",
+}
+
+var regionCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+const (
+	defaultMaxVideos = 20
+	minInterval      = 5 * time.Minute
+)
+
+// Store holds the current matrix and reloads it when the file changes
+type Store struct {
+	path            string
+	defaultInterval time.Duration
+
+	mu       sync.RWMutex
+	entries  []Entry
+	modified time.Time
+}
+
+// NewStore loads FETCH_MATRIX_FILE, or the built-in matrix when no file is
+// configured. A file that fails to load leaves the built-in matrix in place.
+func NewStore(cfg *config.Config) *Store {
+	s := &Store{
+		path:            cfg.FetchMatrixFile,
+		defaultInterval: cfg.FetchInterval,
+		entries:         defaultEntries(cfg.FetchInterval),
+	}
+	if s.path == "" {
+		log.Printf("FETCH_MATRIX_FILE not set, using built-in fetch matrix (%d entries)", len(s.entries))
+		return s
+	}
+	if err := s.reload(); err != nil {
+		log.Printf("Failed to load fetch matrix, using built-in matrix: %v", err)
+	}
+	return s
+}
+
+// defaultEntries is the matrix the service shipped with
+func defaultEntries(interval time.Duration) []Entry {
+	var entries []Entry
+	for _, region := range []string{"US", "IN", "DE", "GB", "CA"} {
+		for _, category := range []string{"10", "24", "25"} { // Music, Entertainment, News & Politics
+			entries = append(entries, Entry{Region: region, Category: category, MaxVideos: defaultMaxVideos, Interval: interval})
+		}
+	}
+	return entries
+}
+
+// Entries returns a copy of the current matrix
+func (s *Store) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// Regions lists the regions in the matrix in the order they first appear
+func (s *Store) Regions() []model.RegionResponse {
+	regions := []model.RegionResponse{}
+	seen := make(map[string]bool)
+	for _, entry := range s.Entries() {
+		if seen[entry.Region] {
+			continue
+		}
+		seen[entry.Region] = true
+		name, ok := regionNames[entry.Region]
+		if !ok {
+			name = entry.Region
+		}
+		regions = append(regions, model.RegionResponse{Code: entry.Region, Name: name})
+	}
+	return regions
+}
+
+// Categories lists the categories fetched for a region, or for any region
+// when region is empty
+func (s *Store) Categories(region string) []model.CategoryResponse {
+	categories := []model.CategoryResponse{}
+	seen := make(map[string]bool)
+	for _, entry := range s.Entries() {
+		if seen[entry.Category] || (region != "" && entry.Region != region) {
+			continue
+		}
+		seen[entry.Category] = true
+		title, ok := utils.CategoryMap[entry.Category]
+		if !ok {
+			title = "General"
+		}
+		categories = append(categories, model.CategoryResponse{ID: entry.Category, Title: title})
+	}
+	return categories
+}
+
+// Watch reloads the file whenever its modification time changes. Kubernetes
+// updates mounted ConfigMaps in place, so edits apply without a restart.
+func (s *Store) Watch(ctx context.Context, every time.Duration) {
+	if s.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(s.path)
+			if err != nil {
+				log.Printf("Failed to stat fetch matrix: %v", err)
+				continue
+			}
+			s.mu.RLock()
+			unchanged := info.ModTime().Equal(s.modified)
+			s.mu.RUnlock()
+			if unchanged {
+				continue
+			}
+			if err := s.reload(); err != nil {
+				log.Printf("Failed to reload fetch matrix, keeping current one: %v", err)
+			}
+		}
+	}
+}
+
+func (s *Store) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	entries, err := parse(data, s.defaultInterval)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	s.mu.Lock()
+	s.entries = entries
+	s.modified = info.ModTime()
+	s.mu.Unlock()
+
+	log.Printf("Loaded fetch matrix from %s: %d entries", s.path, len(entries))
+	return nil
+}
+
+// parse validates a matrix file. maxVideos defaults to 20 and interval to
+// FETCH_INTERVAL; duplicate pairs are rejected rather than merged.
+func parse(data []byte, defaultInterval time.Duration) ([]Entry, error) {
+	var raw []fileEntry
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("matrix has no entries")
+	}
+
+	entries := make([]Entry, 0, len(raw))
+	seen := make(map[string]bool)
+	for i, r := range raw {
+		entry := Entry{
+			Region:    strings.ToUpper(strings.TrimSpace(r.Region)),
+			Category:  strings.TrimSpace(r.Category),
+			MaxVideos: r.MaxVideos,
+			Interval:  defaultInterval,
+		}
+
+		if !regionCode.MatchString(entry.Region) {
+			return nil, fmt.Errorf("entry %d: invalid region %q", i, r.Region)
+		}
+		if entry.Category == "" {
+			return nil, fmt.Errorf("entry %d: category is required", i)
+		}
+		if entry.MaxVideos == 0 {
+			entry.MaxVideos = defaultMaxVideos
+		}
+		if entry.MaxVideos < 1 || entry.MaxVideos > 50 {
+			return nil, fmt.Errorf("entry %d: maxVideos must be between 1 and 50", i)
+		}
+		if r.Interval != "" {
+			interval, err := time.ParseDuration(r.Interval)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: invalid interval: %w", i, err)
+			}
+			entry.Interval = interval
+		}
+		if entry.Interval < minInterval {
+			return nil, fmt.Errorf("entry %d: interval must be at least %v", i, minInterval)
+		}
+		if seen[entry.Key()] {
+			return nil, fmt.Errorf("entry %d: duplicate region/category %s", i, entry.Key())
+		}
+		seen[entry.Key()] = true
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
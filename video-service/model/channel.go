@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Channel is a YouTube channel stored in the channels collection
+type Channel struct {
+	ID              string    `bson:"_id" json:"id"`
+	Title           string    `bson:"title" json:"title"`
+	Description     string    `bson:"description" json:"description"`
+	CustomURL       string    `bson:"customUrl" json:"customUrl,omitempty"`
+	Thumbnail       string    `bson:"thumbnail" json:"thumbnail"`
+	Country         string    `bson:"country" json:"country,omitempty"`
+	SubscriberCount int64     `bson:"subscriberCount" json:"subscriberCount"`
+	VideoCount      int64     `bson:"videoCount" json:"videoCount"`
+	ViewCount       int64     `bson:"viewCount" json:"viewCount"`
+	FetchedAt       time.Time `bson:"fetchedAt" json:"fetchedAt"`
+}
+
+// TrendingChannel ranks a channel by how widely its videos trend
+type TrendingChannel struct {
+	Channel        Channel  `bson:"channel" json:"channel"`
+	Placements     int      `bson:"placements" json:"placements"` // Distinct (video, region) pairs on trending
+	TrendingVideos int      `bson:"trendingVideos" json:"trendingVideos"`
+	Regions        []string `bson:"regions" json:"regions"`
+}
+
+type YouTubeChannelResponse struct {
+	Items []struct {
+		ID      string `json:"id"`
+		Snippet struct {
+			Title       string     `json:"title"`
+			Description string     `json:"description"`
+			CustomURL   string     `json:"customUrl"`
+			Country     string     `json:"country"`
+			Thumbnails  Thumbnails `json:"thumbnails"`
+		} `json:"snippet"`
+		Statistics struct {
+			SubscriberCount string `json:"subscriberCount"`
+			VideoCount      string `json:"videoCount"`
+			ViewCount       string `json:"viewCount"`
+		} `json:"statistics"`
+	} `json:"items"`
+}
@@ -1,13 +1,16 @@
 package model
 
+import "time"
+
 type CommentThreadResponse struct {
-	Items []CommentThread `json:"items"`
-	NextPageToken string   `json:"nextPageToken,omitempty"`
+	Items         []CommentThread `json:"items"`
+	NextPageToken string          `json:"nextPageToken,omitempty"`
 }
 
 type CommentThread struct {
-	ID      string        `json:"id"`
+	ID      string `json:"id"`
 	Snippet struct {
+		VideoID         string  `json:"videoId"`
 		TopLevelComment Comment `json:"topLevelComment"`
 		TotalReplyCount int     `json:"totalReplyCount"`
 	} `json:"snippet"`
@@ -19,8 +22,44 @@ type CommentThread struct {
 type Comment struct {
 	ID      string `json:"id"`
 	Snippet struct {
-		TextDisplay   string `json:"textDisplay"`
-		AuthorDisplayName string `json:"authorDisplayName"`
-		PublishedAt   string `json:"publishedAt"`
+		TextDisplay           string `json:"textDisplay"`
+		AuthorDisplayName     string `json:"authorDisplayName"`
+		AuthorProfileImageURL string `json:"authorProfileImageUrl"`
+		ParentID              string `json:"parentId"`
+		LikeCount             int64  `json:"likeCount"`
+		PublishedAt           string `json:"publishedAt"`
+		UpdatedAt             string `json:"updatedAt"`
 	} `json:"snippet"`
 }
+
+// VideoComment is a comment stored in the comments collection. Top-level
+// comments have an empty ParentID; replies point at their thread.
+type VideoComment struct {
+	ID          string    `bson:"_id" json:"id"`
+	VideoID     string    `bson:"videoId" json:"videoId"`
+	ParentID    string    `bson:"parentId" json:"parentId,omitempty"`
+	Author      string    `bson:"author" json:"author"`
+	AuthorImage string    `bson:"authorImage" json:"authorImage,omitempty"`
+	Text        string    `bson:"text" json:"text"`
+	LikeCount   int64     `bson:"likeCount" json:"likeCount"`
+	ReplyCount  int       `bson:"replyCount" json:"replyCount"`
+	Relevance   int       `bson:"relevance" json:"-"` // Position in YouTube's relevance order
+	PublishedAt time.Time `bson:"publishedAt" json:"publishedAt"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+	FetchedAt   time.Time `bson:"fetchedAt" json:"-"`
+}
+
+// CommentThreadView is a top-level comment with the replies we hold for it
+type CommentThreadView struct {
+	VideoComment
+	Replies []VideoComment `json:"replies"`
+}
+
+// CommentListResponse is returned by /api/comments
+type CommentListResponse struct {
+	VideoID    string              `json:"videoId"`
+	Sort       string              `json:"sort"`
+	Comments   []CommentThreadView `json:"comments"`
+	Count      int                 `json:"count"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// VideoStatsSnapshot is one point in a video's view/like history
+type VideoStatsSnapshot struct {
+	VideoID      string    `bson:"videoId" json:"videoId"`
+	ChannelID    string    `bson:"channelId,omitempty" json:"-"`
+	Region       string    `bson:"region" json:"region"`
+	Origin       string    `bson:"origin,omitempty" json:"-"`
+	ViewCount    int64     `bson:"viewCount" json:"viewCount"`
+	LikeCount    int64     `bson:"likeCount" json:"likeCount"`
+	ViewVelocity float64   `bson:"viewVelocity" json:"viewVelocity"`
+	RecordedAt   time.Time `bson:"recordedAt" json:"recordedAt"`
+}
+
+// VideoHistoryResponse is returned by /api/videos/:id/history
+type VideoHistoryResponse struct {
+	VideoID string               `json:"videoId"`
+	Points  []VideoStatsSnapshot `json:"points"`
+	Count   int                  `json:"count"`
+}
@@ -8,26 +8,68 @@ import (
 
 // Video represents a video stored in MongoDB
 type Video struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	VideoID      string             `bson:"videoId" json:"videoId"`
-	Title        string             `bson:"title" json:"title"`
-	Description  string             `bson:"description" json:"description"`
-	ChannelTitle string             `bson:"channelTitle" json:"channelTitle"`
-	CategoryID   string             `bson:"categoryId" json:"categoryId"`
-	CategoryName string             `bson:"categoryName" json:"categoryName"`
-	Region       string             `bson:"region" json:"region"`
-	PublishedAt  time.Time          `bson:"publishedAt" json:"publishedAt"`
-	Thumbnail    string             `bson:"thumbnail" json:"thumbnail"`
-	VideoURL     string             `bson:"videoUrl" json:"videoUrl"`
-	ViewCount    int64              `bson:"viewCount" json:"viewCount"`
-	LikeCount    int64              `bson:"likeCount" json:"likeCount"`
-	Duration     string             `bson:"duration" json:"duration"`
-	FetchedAt    time.Time          `bson:"fetchedAt" json:"fetchedAt"`
-	Source       struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	VideoID         string             `bson:"videoId" json:"videoId"`
+	Title           string             `bson:"title" json:"title"`
+	Description     string             `bson:"description" json:"description"`
+	ChannelID       string             `bson:"channelId" json:"channelId"`
+	ChannelTitle    string             `bson:"channelTitle" json:"channelTitle"`
+	CategoryID      string             `bson:"categoryId" json:"categoryId"`
+	CategoryName    string             `bson:"categoryName" json:"categoryName"`
+	Region          string             `bson:"region" json:"region"`
+	PublishedAt     time.Time          `bson:"publishedAt" json:"publishedAt"`
+	Thumbnail       string             `bson:"thumbnail" json:"thumbnail"`
+	VideoURL        string             `bson:"videoUrl" json:"videoUrl"`
+	ViewCount       int64              `bson:"viewCount" json:"viewCount"`
+	LikeCount       int64              `bson:"likeCount" json:"likeCount"`
+	Duration        string             `bson:"duration" json:"duration"` // ISO-8601 as YouTube reports it
+	DurationSeconds int64              `bson:"durationSeconds" json:"durationSeconds"`
+	Format          string             `bson:"format" json:"format"`             // FormatShort, FormatStandard or FormatLong; empty for live streams
+	ViewVelocity    float64            `bson:"viewVelocity" json:"viewVelocity"` // Views per hour
+	LikeRatio       float64            `bson:"likeRatio" json:"likeRatio"`
+	FetchedAt       time.Time          `bson:"fetchedAt" json:"fetchedAt"`
+	Origin          string             `bson:"origin" json:"origin"` // OriginTrending or OriginSearch
+	Source          struct {
 		Name string `json:"name" bson:"name"`
 	} `json:"source" bson:"source"`
+
+	// Set by the availability verifier; videos without it count as available
+	Availability          string    `bson:"availability,omitempty" json:"availability,omitempty"`
+	UnavailableReason     string    `bson:"unavailableReason,omitempty" json:"unavailableReason,omitempty"`
+	UnavailableSince      time.Time `bson:"unavailableSince,omitempty" json:"-"`
+	AvailabilityCheckedAt time.Time `bson:"availabilityCheckedAt,omitempty" json:"-"`
 }
 
+// How a video reached the videos collection. Search results are kept for
+// local search but stay out of the trending and category listings.
+const (
+	OriginTrending = "trending"
+	OriginSearch   = "search"
+)
+
+// Availability of a stored video. Unavailable videos are left out of every
+// listing and deleted once AVAILABILITY_GRACE_PERIOD has passed.
+const (
+	AvailabilityAvailable   = "available"
+	AvailabilityUnavailable = "unavailable"
+)
+
+// Why a video is unavailable
+const (
+	ReasonDeleted       = "deleted"       // Missing from videos.list
+	ReasonPrivate       = "private"       // Made private by the uploader
+	ReasonRemoved       = "removed"       // Upload rejected or removed by YouTube
+	ReasonRegionBlocked = "regionBlocked" // Blocked in the video's region
+	ReasonNotEmbeddable = "notEmbeddable" // Embedding disabled, so our player can't show it
+)
+
+// Video formats by duration. Shorts are what the vertical-scroll UI asks for.
+const (
+	FormatShort    = "short"
+	FormatStandard = "standard"
+	FormatLong     = "long"
+)
+
 // FetchRequest represents a video fetch request via NATS
 type FetchRequest struct {
 	Region    string `json:"region"`
@@ -56,6 +98,7 @@ type YouTubeVideoItem struct {
 	Snippet struct {
 		Title        string     `json:"title"`
 		Description  string     `json:"description"`
+		ChannelID    string     `json:"channelId"`
 		ChannelTitle string     `json:"channelTitle"`
 		CategoryID   string     `json:"categoryId"`
 		PublishedAt  string     `json:"publishedAt"`
@@ -66,8 +109,18 @@ type YouTubeVideoItem struct {
 		LikeCount string `json:"likeCount"`
 	} `json:"statistics"`
 	ContentDetails struct {
-		Duration string `json:"duration"`
+		Duration          string `json:"duration"`
+		RegionRestriction struct {
+			Allowed []string `json:"allowed"`
+			Blocked []string `json:"blocked"`
+		} `json:"regionRestriction"`
 	} `json:"contentDetails"`
+	// Only requested by the availability verifier
+	Status struct {
+		PrivacyStatus string `json:"privacyStatus"`
+		UploadStatus  string `json:"uploadStatus"`
+		Embeddable    bool   `json:"embeddable"`
+	} `json:"status"`
 }
 
 type YouTubeTrendingResponse struct {
@@ -0,0 +1,119 @@
+package personalize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Candidate is an item sent to analytics-service for ranking
+type Candidate struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Region      string    `json:"region,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	PublishedAt time.Time `json:"published_at,omitempty"`
+	Popularity  float64   `json:"popularity"`
+}
+
+// Ranking is analytics-service's score for one candidate
+type Ranking struct {
+	ID          string      `json:"id"`
+	Score       float64     `json:"score"`
+	Explanation Explanation `json:"explanation"`
+}
+
+// Explanation breaks a personalised score into its components
+type Explanation struct {
+	Recency    float64  `json:"recency"`
+	Popularity float64  `json:"popularity"`
+	Affinity   float64  `json:"affinity"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+type rankResponse struct {
+	Personalized bool      `json:"personalized"`
+	Rankings     []Ranking `json:"rankings"`
+}
+
+// Client calls the analytics-service personalisation API
+type Client struct {
+	baseURL    string
+	enabled    bool
+	httpClient *http.Client
+}
+
+// NewClient reads PERSONALIZATION_URL, PERSONALIZATION_ENABLED and
+// PERSONALIZATION_TIMEOUT_MS from the environment
+func NewClient() *Client {
+	timeout := 300
+	if value, err := strconv.Atoi(os.Getenv("PERSONALIZATION_TIMEOUT_MS")); err == nil && value > 0 {
+		timeout = value
+	}
+
+	baseURL := os.Getenv("PERSONALIZATION_URL")
+	if baseURL == "" {
+		baseURL = "http://analytics-service:8080"
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		enabled:    os.Getenv("PERSONALIZATION_ENABLED") != "false",
+		httpClient: &http.Client{Timeout: time.Duration(timeout) * time.Millisecond},
+	}
+}
+
+// SessionFor returns the session to personalise for, or "" when the request
+// opted out with personalize=false or carries no session
+func (c *Client) SessionFor(ctx *gin.Context) string {
+	if c == nil || !c.enabled || ctx.Query("personalize") == "false" {
+		return ""
+	}
+	if sessionID := ctx.Query("session_id"); sessionID != "" {
+		return sessionID
+	}
+	return ctx.GetHeader("X-Session-ID")
+}
+
+// Rank returns rankings keyed by candidate ID in best-first order. personalized
+// is false when the session has no history and the order is recency/popularity only.
+func (c *Client) Rank(ctx context.Context, sessionID string, candidates []Candidate) (rankings []Ranking, personalized bool, err error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"session_id": sessionID,
+		"candidates": candidates,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode rank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/personalize/rank", bytes.NewReader(body))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create rank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("rank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("rank request returned status %d", resp.StatusCode)
+	}
+
+	var result rankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, false, fmt.Errorf("failed to decode rank response: %w", err)
+	}
+	return result.Rankings, result.Personalized, nil
+}
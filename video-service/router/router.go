@@ -1,14 +1,19 @@
 package router
 
 import (
+	"video-service/config"
+	"video-service/fetcher"
 	"video-service/handler"
+	"video-service/matrix"
+	"video-service/schema"
+	"video-service/service"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func Setup(db *mongo.Database) *gin.Engine {
+func Setup(cfg *config.Config, db *mongo.Database, f *fetcher.Fetcher, m *matrix.Store, s *service.Service) *gin.Engine {
 	r := gin.Default()
 
 	// CORS middleware
@@ -22,15 +27,25 @@ func Setup(db *mongo.Database) *gin.Engine {
 
 	// Initialize handlers with database
 	handler.InitDB(db)
+	handler.InitSearch(f, cfg)
+	handler.InitMatrix(m)
+	handler.InitService(s)
 
 	r.GET("/regions", handler.GetRegions)
 	r.GET("/api/regions", handler.GetRegions)
 	r.GET("/api/categories", handler.GetCategories)
 	r.GET("/api/videos", handler.GetVideos)
+	r.GET("/api/videos/:id/history", handler.GetVideoHistory)
 	r.GET("/api/trending", handler.GetTrending)
 	r.GET("/api/search", handler.SearchVideos)
 	r.GET("/api/comments", handler.GetComments)
 	r.GET("/api/videostats", handler.GetVideoStats)
+	r.GET("/api/channels/trending", handler.GetTrendingChannels)
+	r.GET("/api/channels/:id", handler.GetChannel)
+	r.GET("/api/channels/:id/videos", handler.GetChannelVideos)
+	r.GET("/api/openapi.yaml", func(c *gin.Context) {
+		c.Data(200, "application/yaml", schema.OpenAPI)
+	})
 
 	// Health check endpoint
 	r.GET("/", func(c *gin.Context) {
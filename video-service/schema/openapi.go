@@ -0,0 +1,8 @@
+package schema
+
+import _ "embed"
+
+// OpenAPI describes the native response shapes; served at /api/openapi.yaml
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"time"
+	"video-service/model"
+	"video-service/personalize"
+)
+
+// Response shapes selected with the format query parameter
+const (
+	ShapeNative  = "native"
+	ShapeYouTube = "youtube"
+)
+
+// Video is the native response shape for a video, described in openapi.yaml
+type Video struct {
+	ID              string           `json:"id"`
+	Title           string           `json:"title"`
+	Description     string           `json:"description"`
+	URL             string           `json:"url"`
+	Thumbnail       string           `json:"thumbnail"`
+	PublishedAt     time.Time        `json:"publishedAt"`
+	Region          string           `json:"region"`
+	Channel         Channel          `json:"channel"`
+	Category        Category         `json:"category"`
+	Duration        Duration         `json:"duration"`
+	Stats           Stats            `json:"stats"`
+	Personalization *Personalization `json:"personalization,omitempty"`
+}
+
+type Channel struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+}
+
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Duration struct {
+	Seconds int64  `json:"seconds"`
+	Format  string `json:"format,omitempty"`
+	ISO     string `json:"iso"`
+}
+
+type Stats struct {
+	Views        int64   `json:"views"`
+	Likes        int64   `json:"likes"`
+	LikeRatio    float64 `json:"likeRatio"`
+	ViewVelocity float64 `json:"viewVelocity"` // Views per hour
+}
+
+// Personalization is attached when the request asked for explain=true
+type Personalization struct {
+	Score       float64                 `json:"score"`
+	Explanation personalize.Explanation `json:"explanation"`
+}
+
+// VideoList wraps native video listings
+type VideoList struct {
+	Videos []Video `json:"videos"`
+	Count  int     `json:"count"`
+}
+
+// NewVideo maps a stored video to the native shape
+func NewVideo(video model.Video) Video {
+	return Video{
+		ID:          video.VideoID,
+		Title:       video.Title,
+		Description: video.Description,
+		URL:         video.VideoURL,
+		Thumbnail:   video.Thumbnail,
+		PublishedAt: video.PublishedAt,
+		Region:      video.Region,
+		Channel:     Channel{ID: video.ChannelID, Title: video.ChannelTitle},
+		Category:    Category{ID: video.CategoryID, Name: video.CategoryName},
+		Duration: Duration{
+			Seconds: video.DurationSeconds,
+			Format:  video.Format,
+			ISO:     video.Duration,
+		},
+		Stats: Stats{
+			Views:        video.ViewCount,
+			Likes:        video.LikeCount,
+			LikeRatio:    video.LikeRatio,
+			ViewVelocity: video.ViewVelocity,
+		},
+	}
+}
+
+// YouTubeItem is the legacy shape modelled on YouTube's search results,
+// which the existing frontend reads. New fields go on Video only.
+type YouTubeItem struct {
+	ID struct {
+		VideoID string `json:"videoId"`
+	} `json:"id"`
+	Snippet struct {
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		ChannelTitle string `json:"channelTitle"`
+		PublishedAt  string `json:"publishedAt"`
+		Thumbnails   struct {
+			Medium  YouTubeThumbnail `json:"medium"`
+			Default YouTubeThumbnail `json:"default"`
+		} `json:"thumbnails"`
+		CategoryID string `json:"categoryId"`
+	} `json:"snippet"`
+	VideoURL        string           `json:"videoURL"`
+	ViewCount       int64            `json:"viewCount"`
+	LikeCount       int64            `json:"likeCount"`
+	ViewVelocity    float64          `json:"viewVelocity"`
+	LikeRatio       float64          `json:"likeRatio"`
+	Duration        string           `json:"duration"`
+	DurationSeconds int64            `json:"durationSeconds"`
+	Format          string           `json:"format"`
+	Region          string           `json:"region"`
+	CategoryName    string           `json:"categoryName"`
+	Personalization *Personalization `json:"personalization,omitempty"`
+}
+
+type YouTubeThumbnail struct {
+	URL string `json:"url"`
+}
+
+// NewYouTubeItem maps a stored video to the YouTube-compatible shape. This
+// is the only place that shape is built.
+func NewYouTubeItem(video model.Video) YouTubeItem {
+	var item YouTubeItem
+	item.ID.VideoID = video.VideoID
+	item.Snippet.Title = video.Title
+	item.Snippet.Description = video.Description
+	item.Snippet.ChannelTitle = video.ChannelTitle
+	item.Snippet.PublishedAt = video.PublishedAt.Format(time.RFC3339)
+	item.Snippet.Thumbnails.Medium.URL = video.Thumbnail
+	item.Snippet.Thumbnails.Default.URL = video.Thumbnail
+	item.Snippet.CategoryID = video.CategoryID
+	item.VideoURL = video.VideoURL
+	item.ViewCount = video.ViewCount
+	item.LikeCount = video.LikeCount
+	item.ViewVelocity = video.ViewVelocity
+	item.LikeRatio = video.LikeRatio
+	item.Duration = video.Duration
+	item.DurationSeconds = video.DurationSeconds
+	item.Format = video.Format
+	item.Region = video.Region
+	item.CategoryName = video.CategoryName
+	return item
+}
@@ -19,8 +19,6 @@ var CategoryMap = map[string]string{
 	"28": "Science & Technology",
 }
 
-var SupportedRegions = []string{"IN","US","DE"}
-
 /*curl commands =>
 
 curl "http://localhost:8080/regions"
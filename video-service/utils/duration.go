@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// isoDuration matches the ISO-8601 durations YouTube returns, e.g. PT4M13S,
+// PT1H2M or P1DT3H. Years, months and weeks never appear for videos.
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseISODuration converts a YouTube ISO-8601 duration to a time.Duration.
+// Live streams report P0D, which parses to zero.
+func ParseISODuration(value string) (time.Duration, error) {
+	match := isoDuration.FindStringSubmatch(value)
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid ISO-8601 duration %q", value)
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO-8601 duration %q: %w", value, err)
+		}
+		total += time.Duration(n) * unit
+	}
+	return total, nil
+}
@@ -7,33 +7,32 @@ import (
 	"time"
 	"video-service/config"
 	"video-service/fetcher"
+	"video-service/matrix"
 	"video-service/model"
 
 	"github.com/nats-io/nats.go"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type Worker struct {
 	config     *config.Config
 	natsConn   *nats.Conn
 	fetcher    *fetcher.Fetcher
+	matrix     *matrix.Store
 	cancelFunc context.CancelFunc
 }
 
-func NewWorker(cfg *config.Config, db *mongo.Database) (*Worker, error) {
+func NewWorker(cfg *config.Config, f *fetcher.Fetcher, m *matrix.Store) (*Worker, error) {
 	// Connect to NATS
 	nc, err := nats.Connect(cfg.NATSUrl)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create fetcher
-	fetcher := fetcher.NewFetcher(cfg, db)
-
 	return &Worker{
 		config:   cfg,
 		natsConn: nc,
-		fetcher:  fetcher,
+		fetcher:  f,
+		matrix:   m,
 	}, nil
 }
 
@@ -56,6 +55,8 @@ func (w *Worker) Start(ctx context.Context) error {
 
 	// Start scheduler for periodic fetches
 	go w.startScheduler(workerCtx)
+	go w.startCommentScheduler(workerCtx)
+	go w.startAvailabilityScheduler(workerCtx)
 
 	log.Println("Workers started successfully")
 	return nil
@@ -94,19 +95,23 @@ func (w *Worker) handleFetchRequest(ctx context.Context, msg *nats.Msg) {
 	log.Printf("Completed fetch request: %s", req.RequestID)
 }
 
+// schedulerTick is how often the scheduler checks for due matrix entries
+const schedulerTick = time.Minute
+
+// startScheduler publishes a fetch for each matrix entry whenever its
+// interval has passed. The matrix is read on every tick, so reloaded entries
+// take effect without a restart; new entries are fetched right away.
 func (w *Worker) startScheduler(ctx context.Context) {
 	log.Println("Scheduler started on this instance")
 	log.Println("Scheduling periodic video fetches")
 
-	ticker := time.NewTicker(w.config.FetchInterval)
+	ticker := time.NewTicker(schedulerTick)
 	defer ticker.Stop()
 
-	// Define regions and categories to fetch
-	regions := []string{"US", "IN", "DE", "GB", "CA"}
-	categories := []string{"10", "24", "25"} // Music, Entertainment, News & Politics
+	lastRun := make(map[string]time.Time)
 
 	// Initial fetch
-	w.scheduleVideoFetches(regions, categories)
+	w.scheduleVideoFetches(ctx, lastRun)
 
 	for {
 		select {
@@ -114,38 +119,99 @@ func (w *Worker) startScheduler(ctx context.Context) {
 			log.Println("Scheduler stopped")
 			return
 		case <-ticker.C:
-			log.Println("Triggering scheduled video fetch")
-			w.scheduleVideoFetches(regions, categories)
+			w.scheduleVideoFetches(ctx, lastRun)
 		}
 	}
 }
 
-func (w *Worker) scheduleVideoFetches(regions, categories []string) {
-	for _, region := range regions {
-		for _, category := range categories {
-			// Create fetch request
-			req := model.FetchRequest{
-				Region:    region,
-				Category:  category,
-				MaxVideos: 20,
-				Priority:  "normal",
-				RequestID: w.generateRequestID(region, category),
-			}
-
-			// Publish to NATS
-			data, _ := json.Marshal(req)
-			if err := w.natsConn.Publish("fetch.videos", data); err != nil {
-				log.Printf("Failed to publish fetch request: %v", err)
-			} else {
-				log.Printf("Scheduled fetch for region %s, category %s", region, category)
-			}
-
-			// Rate limiting between requests
-			time.Sleep(w.config.RateLimit)
+// startCommentScheduler keeps stored comments of trending videos fresh. The
+// first run is delayed so the initial video fetch lands first.
+func (w *Worker) startCommentScheduler(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(time.Minute):
+		w.fetcher.RefreshTrendingComments(ctx)
+	}
+
+	ticker := time.NewTicker(w.config.CommentRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Comment scheduler stopped")
+			return
+		case <-ticker.C:
+			log.Println("Triggering scheduled comment refresh")
+			w.fetcher.RefreshTrendingComments(ctx)
 		}
 	}
 }
 
+// startAvailabilityScheduler re-checks stored videos against YouTube so
+// deleted, private and region-blocked ones drop out of the listings
+func (w *Worker) startAvailabilityScheduler(ctx context.Context) {
+	ticker := time.NewTicker(w.config.AvailabilityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Availability scheduler stopped")
+			return
+		case <-ticker.C:
+			log.Println("Triggering scheduled availability check")
+			w.fetcher.VerifyAvailability(ctx)
+		}
+	}
+}
+
+func (w *Worker) scheduleVideoFetches(ctx context.Context, lastRun map[string]time.Time) {
+	entries := w.matrix.Entries()
+
+	// Forget entries that left the matrix so they start fresh if re-added
+	current := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		current[entry.Key()] = true
+	}
+	for key := range lastRun {
+		if !current[key] {
+			delete(lastRun, key)
+		}
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if last, ok := lastRun[entry.Key()]; ok && time.Since(last) < entry.Interval {
+			continue
+		}
+
+		// Create fetch request
+		req := model.FetchRequest{
+			Region:    entry.Region,
+			Category:  entry.Category,
+			MaxVideos: entry.MaxVideos,
+			Priority:  "normal",
+			RequestID: w.generateRequestID(entry.Region, entry.Category),
+		}
+
+		// Publish to NATS
+		data, _ := json.Marshal(req)
+		if err := w.natsConn.Publish("fetch.videos", data); err != nil {
+			log.Printf("Failed to publish fetch request: %v", err)
+			continue
+		}
+		lastRun[entry.Key()] = time.Now()
+		log.Printf("Scheduled fetch for region %s, category %s", entry.Region, entry.Category)
+
+		// Rate limiting between requests
+		time.Sleep(w.config.RateLimit)
+	}
+}
+
 func (w *Worker) generateRequestID(region, category string) string {
 	timestamp := time.Now().Format("20060102-150405")
 	return region + "-" + category + "-" + timestamp
@@ -0,0 +1,66 @@
+// Package youtube wraps the YouTube Data API calls video-service makes, so
+// the fetcher, service and handlers can run against recorded fixtures.
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"video-service/config"
+	"video-service/model"
+)
+
+// Client is the YouTube Data API surface used by video-service. Quota costs
+// are per call: search is 100 units, everything else 1.
+type Client interface {
+	// Trending returns the mostPopular chart for a region, optionally narrowed
+	// to a category
+	Trending(ctx context.Context, region, category string, maxResults int) ([]model.YouTubeVideoItem, error)
+	// Videos returns full details for up to 50 IDs; unknown IDs are left out
+	Videos(ctx context.Context, ids []string) ([]model.YouTubeVideoItem, error)
+	// VideoStatus returns status and contentDetails for up to 50 IDs. Deleted
+	// videos are left out, and so are private ones unless the caller owns them.
+	VideoStatus(ctx context.Context, ids []string) ([]model.YouTubeVideoItem, error)
+	// Search returns the IDs of matching videos. duration is YouTube's
+	// videoDuration bucket (short, medium, long) or empty.
+	Search(ctx context.Context, query, region string, maxResults int, duration string) ([]string, error)
+	Categories(ctx context.Context, region string) ([]model.CategoryResponse, error)
+	Regions(ctx context.Context) ([]model.RegionResponse, error)
+	// CommentThreads returns one page of up to 100 threads in relevance order
+	CommentThreads(ctx context.Context, videoID, pageToken string) (model.CommentThreadResponse, error)
+	// Channels returns details for up to 50 channel IDs
+	Channels(ctx context.Context, ids []string) (model.YouTubeChannelResponse, error)
+	Statistics(ctx context.Context, videoID string) (model.VideoStatsResponse, error)
+}
+
+// MaxIDsPerRequest is the most IDs videos.list and channels.list accept
+const MaxIDsPerRequest = 50
+
+// APIError is a non-200 response from the API
+type APIError struct {
+	StatusCode int
+	Reason     string // e.g. quotaExceeded, videoNotFound
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("YouTube API HTTP %d (%s): %s", e.StatusCode, e.Reason, e.Message)
+	}
+	return fmt.Sprintf("YouTube API HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+var (
+	_ Client = (*HTTPClient)(nil)
+	_ Client = (*FakeClient)(nil)
+)
+
+// New returns the fixture client when YOUTUBE_FIXTURES_DIR is set and the
+// API client otherwise
+func New(cfg *config.Config) Client {
+	if cfg.YouTubeFixturesDir != "" {
+		log.Printf("Serving YouTube API calls from fixtures in %s", cfg.YouTubeFixturesDir)
+		return NewFakeClient(cfg.YouTubeFixturesDir)
+	}
+	return NewHTTPClient(cfg.YouTubeAPIKey)
+}
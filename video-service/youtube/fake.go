@@ -0,0 +1,170 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"video-service/model"
+)
+
+// FakeClient answers from recorded API responses in a directory such as
+// youtube/testdata. Set YOUTUBE_FIXTURES_DIR to run the service offline.
+//
+//	trending.json    videos.list chart=mostPopular
+//	videos.json      videos.list by ID, also used for status and statistics
+//	search.json      search.list
+//	categories.json  videoCategories.list
+//	regions.json     i18nRegions.list
+//	comments.json    commentThreads.list; later pages are comments-<pageToken>.json
+//	channels.json    channels.list
+//
+// Region and query are ignored; ID lookups, category and maxResults are applied.
+type FakeClient struct {
+	dir string
+}
+
+func NewFakeClient(dir string) *FakeClient {
+	return &FakeClient{dir: dir}
+}
+
+func (c *FakeClient) load(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		return fmt.Errorf("fixture %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("fixture %s: %w", name, err)
+	}
+	return nil
+}
+
+func (c *FakeClient) Trending(ctx context.Context, region, category string, maxResults int) ([]model.YouTubeVideoItem, error) {
+	var response model.YouTubeVideoResponse
+	if err := c.load("trending.json", &response); err != nil {
+		return nil, err
+	}
+
+	var items []model.YouTubeVideoItem
+	for _, item := range response.Items {
+		if category != "" && category != "0" && item.Snippet.CategoryID != category {
+			continue
+		}
+		if len(items) == maxResults {
+			break
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (c *FakeClient) Videos(ctx context.Context, ids []string) ([]model.YouTubeVideoItem, error) {
+	if len(ids) > MaxIDsPerRequest {
+		return nil, fmt.Errorf("videos.list takes at most %d IDs, got %d", MaxIDsPerRequest, len(ids))
+	}
+
+	var response model.YouTubeVideoResponse
+	if err := c.load("videos.json", &response); err != nil {
+		return nil, err
+	}
+
+	wanted := idSet(ids)
+	var items []model.YouTubeVideoItem
+	for _, item := range response.Items {
+		if wanted[item.ID] {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (c *FakeClient) VideoStatus(ctx context.Context, ids []string) ([]model.YouTubeVideoItem, error) {
+	return c.Videos(ctx, ids)
+}
+
+func (c *FakeClient) Search(ctx context.Context, query, region string, maxResults int, duration string) ([]string, error) {
+	var response model.YouTubeTrendingResponse
+	if err := c.load("search.json", &response); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, item := range response.Items {
+		if len(ids) == maxResults {
+			break
+		}
+		if item.ID.VideoID != "" {
+			ids = append(ids, item.ID.VideoID)
+		}
+	}
+	return ids, nil
+}
+
+func (c *FakeClient) Categories(ctx context.Context, region string) ([]model.CategoryResponse, error) {
+	var response model.CategoryListResponse
+	if err := c.load("categories.json", &response); err != nil {
+		return nil, err
+	}
+	return categoriesFrom(response), nil
+}
+
+func (c *FakeClient) Regions(ctx context.Context) ([]model.RegionResponse, error) {
+	var response model.RegionListResponse
+	if err := c.load("regions.json", &response); err != nil {
+		return nil, err
+	}
+	return regionsFrom(response), nil
+}
+
+func (c *FakeClient) CommentThreads(ctx context.Context, videoID, pageToken string) (model.CommentThreadResponse, error) {
+	name := "comments.json"
+	if pageToken != "" {
+		name = "comments-" + pageToken + ".json"
+	}
+
+	var response model.CommentThreadResponse
+	err := c.load(name, &response)
+	return response, err
+}
+
+func (c *FakeClient) Channels(ctx context.Context, ids []string) (model.YouTubeChannelResponse, error) {
+	var response model.YouTubeChannelResponse
+	if err := c.load("channels.json", &response); err != nil {
+		return response, err
+	}
+
+	wanted := idSet(ids)
+	items := response.Items[:0]
+	for _, item := range response.Items {
+		if wanted[item.ID] {
+			items = append(items, item)
+		}
+	}
+	response.Items = items
+	return response, nil
+}
+
+func (c *FakeClient) Statistics(ctx context.Context, videoID string) (model.VideoStatsResponse, error) {
+	var response model.VideoStatsResponse
+	if err := c.load("videos.json", &response); err != nil {
+		return response, err
+	}
+
+	items := response.Items[:0]
+	for _, item := range response.Items {
+		if item.ID == videoID {
+			items = append(items, item)
+		}
+	}
+	response.Items = items
+	return response, nil
+}
+
+func idSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
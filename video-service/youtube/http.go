@@ -0,0 +1,225 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"video-service/model"
+)
+
+const apiBase = "https://www.googleapis.com/youtube/v3"
+
+// HTTPClient calls the real YouTube Data API
+type HTTPClient struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTPClient returns a client authenticating with an API key
+func NewHTTPClient(apiKey string) *HTTPClient {
+	return &HTTPClient{
+		apiKey:     apiKey,
+		baseURL:    apiBase,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *HTTPClient) get(ctx context.Context, resource string, params url.Values, v interface{}) error {
+	params.Set("key", c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+resource+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", resource, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", resource, err)
+	}
+	return nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) == nil {
+		if body.Error.Message != "" {
+			apiErr.Message = body.Error.Message
+		}
+		if len(body.Error.Errors) > 0 {
+			apiErr.Reason = body.Error.Errors[0].Reason
+		}
+	}
+	return apiErr
+}
+
+func (c *HTTPClient) Trending(ctx context.Context, region, category string, maxResults int) ([]model.YouTubeVideoItem, error) {
+	params := url.Values{
+		"part":       {"snippet,statistics,contentDetails"},
+		"chart":      {"mostPopular"},
+		"regionCode": {region},
+		"maxResults": {strconv.Itoa(maxResults)},
+	}
+	if category != "" && category != "0" {
+		params.Set("videoCategoryId", category)
+	}
+
+	var response model.YouTubeVideoResponse
+	if err := c.get(ctx, "videos", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (c *HTTPClient) Videos(ctx context.Context, ids []string) ([]model.YouTubeVideoItem, error) {
+	return c.videosByID(ctx, ids, "snippet,statistics,contentDetails")
+}
+
+func (c *HTTPClient) VideoStatus(ctx context.Context, ids []string) ([]model.YouTubeVideoItem, error) {
+	return c.videosByID(ctx, ids, "status,contentDetails")
+}
+
+func (c *HTTPClient) videosByID(ctx context.Context, ids []string, part string) ([]model.YouTubeVideoItem, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if len(ids) > MaxIDsPerRequest {
+		return nil, fmt.Errorf("videos.list takes at most %d IDs, got %d", MaxIDsPerRequest, len(ids))
+	}
+
+	params := url.Values{
+		"part":       {part},
+		"id":         {strings.Join(ids, ",")},
+		"maxResults": {strconv.Itoa(len(ids))},
+	}
+
+	var response model.YouTubeVideoResponse
+	if err := c.get(ctx, "videos", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+func (c *HTTPClient) Search(ctx context.Context, query, region string, maxResults int, duration string) ([]string, error) {
+	params := url.Values{
+		"part":       {"id"},
+		"type":       {"video"},
+		"q":          {query},
+		"regionCode": {region},
+		"maxResults": {strconv.Itoa(maxResults)},
+	}
+	if duration != "" {
+		params.Set("videoDuration", duration)
+	}
+
+	var response model.YouTubeTrendingResponse
+	if err := c.get(ctx, "search", params, &response); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, item := range response.Items {
+		if item.ID.VideoID != "" {
+			ids = append(ids, item.ID.VideoID)
+		}
+	}
+	return ids, nil
+}
+
+func (c *HTTPClient) Categories(ctx context.Context, region string) ([]model.CategoryResponse, error) {
+	params := url.Values{"part": {"snippet"}, "regionCode": {region}}
+
+	var response model.CategoryListResponse
+	if err := c.get(ctx, "videoCategories", params, &response); err != nil {
+		return nil, err
+	}
+	return categoriesFrom(response), nil
+}
+
+func (c *HTTPClient) Regions(ctx context.Context) ([]model.RegionResponse, error) {
+	params := url.Values{"part": {"snippet"}}
+
+	var response model.RegionListResponse
+	if err := c.get(ctx, "i18nRegions", params, &response); err != nil {
+		return nil, err
+	}
+	return regionsFrom(response), nil
+}
+
+func (c *HTTPClient) CommentThreads(ctx context.Context, videoID, pageToken string) (model.CommentThreadResponse, error) {
+	params := url.Values{
+		"part":       {"snippet,replies"},
+		"videoId":    {videoID},
+		"order":      {"relevance"},
+		"maxResults": {"100"},
+		"textFormat": {"plainText"},
+	}
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	var response model.CommentThreadResponse
+	err := c.get(ctx, "commentThreads", params, &response)
+	return response, err
+}
+
+func (c *HTTPClient) Channels(ctx context.Context, ids []string) (model.YouTubeChannelResponse, error) {
+	var response model.YouTubeChannelResponse
+	if len(ids) > MaxIDsPerRequest {
+		return response, fmt.Errorf("channels.list takes at most %d IDs, got %d", MaxIDsPerRequest, len(ids))
+	}
+
+	params := url.Values{"part": {"snippet,statistics"}, "id": {strings.Join(ids, ",")}}
+	err := c.get(ctx, "channels", params, &response)
+	return response, err
+}
+
+func (c *HTTPClient) Statistics(ctx context.Context, videoID string) (model.VideoStatsResponse, error) {
+	params := url.Values{"part": {"snippet,statistics"}, "id": {videoID}}
+
+	var response model.VideoStatsResponse
+	err := c.get(ctx, "videos", params, &response)
+	return response, err
+}
+
+// categoriesFrom keeps the categories uploads can be filed under
+func categoriesFrom(response model.CategoryListResponse) []model.CategoryResponse {
+	categories := []model.CategoryResponse{}
+	for _, item := range response.Items {
+		if item.Snippet.Assignable {
+			categories = append(categories, model.CategoryResponse{ID: item.ID, Title: item.Snippet.Title})
+		}
+	}
+	return categories
+}
+
+func regionsFrom(response model.RegionListResponse) []model.RegionResponse {
+	regions := []model.RegionResponse{}
+	for _, item := range response.Items {
+		regions = append(regions, model.RegionResponse{Code: item.ID, Name: item.Snippet.Name})
+	}
+	return regions
+}
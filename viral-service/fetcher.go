@@ -1,147 +1,181 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// FetchRedditViral fetches viral stories from Reddit
-func FetchRedditViral() ([]ViralStory, error) {
-	subreddits := []string{"worldnews", "news", "technology"}
-	var allStories []ViralStory
-
-	client := &http.Client{Timeout: 10 * time.Second}
+// redditHeaders makes requests look like a browser; old.reddit.com rejects
+// obvious bots
+var redditHeaders = map[string]string{
+	"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Accept-Language": "en-US,en;q=0.9",
+}
 
-	for _, subreddit := range subreddits {
+// FetchRedditViral fetches viral stories from the given subreddits. Requests
+// share the Reddit host rate limit, so two workers are enough.
+func FetchRedditViral(ctx context.Context, subreddits []string) ([]ViralStory, error) {
+	perSubreddit, err := fetchEach(ctx, subreddits, 2, func(ctx context.Context, subreddit string) ([]ViralStory, bool, error) {
 		url := fmt.Sprintf("https://old.reddit.com/r/%s/hot.json?limit=25", subreddit)
-
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			log.Printf("Error creating request for r/%s: %v", subreddit, err)
-			continue
-		}
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-
-		resp, err := client.Do(req)
-		if err != nil {
+		var redditResp RedditResponse
+		if err := getJSON(ctx, url, &redditResp, redditHeaders); err != nil {
 			log.Printf("Error fetching from r/%s: %v", subreddit, err)
-			continue
+			return nil, false, err
 		}
-		defer resp.Body.Close()
+		stories := redditStories(redditResp)
+		log.Printf("Fetched %d viral stories from r/%s", len(redditResp.Data.Children), subreddit)
+		return stories, true, nil
+	})
 
-		if resp.StatusCode != 200 {
-			log.Printf("Reddit API returned status %d for r/%s", resp.StatusCode, subreddit)
-			continue
-		}
+	var allStories []ViralStory
+	for _, stories := range perSubreddit {
+		allStories = append(allStories, stories...)
+	}
+	return allStories, err
+}
 
-		var redditResp RedditResponse
-		if err := json.NewDecoder(resp.Body).Decode(&redditResp); err != nil {
-			log.Printf("Error decoding Reddit response for r/%s: %v", subreddit, err)
+func redditStories(redditResp RedditResponse) []ViralStory {
+	var stories []ViralStory
+	for _, post := range redditResp.Data.Children {
+		// Filter out low-engagement posts
+		if post.Data.Score < 100 {
 			continue
 		}
 
-		for _, post := range redditResp.Data.Children {
-			// Filter out low-engagement posts
-			if post.Data.Score < 100 {
-				continue
-			}
-
-			// Calculate viral score
-			viralScore := calculateViralScore(post.Data.Score, post.Data.NumComments, 0)
-
-			imageURL := post.Data.Thumbnail
-			if imageURL == "self" || imageURL == "default" || imageURL == "nsfw" {
-				imageURL = ""
-			}
-
-			story := ViralStory{
-				ID:          "reddit_" + post.Data.ID,
-				Title:       post.Data.Title,
-				Description: truncateText(post.Data.Selftext, 300),
-				URL:         "https://reddit.com" + post.Data.Permalink,
-				ImageURL:    imageURL,
-				Source:      "reddit",
-				SourceID:    post.Data.ID,
-				Author:      post.Data.Author,
-				Category:    post.Data.Subreddit,
-				PublishedAt: time.Unix(int64(post.Data.Created), 0),
-				FetchedAt:   time.Now(),
-				ViralScore:  viralScore,
-				Upvotes:     post.Data.Score,
-				Comments:    post.Data.NumComments,
-				Shares:      0,
-				Engagement:  float64(post.Data.Score+post.Data.NumComments) / 100.0,
-			}
-
-			allStories = append(allStories, story)
+		imageURL := post.Data.Thumbnail
+		if imageURL == "self" || imageURL == "default" || imageURL == "nsfw" || imageURL == "spoiler" {
+			imageURL = ""
 		}
 
-		log.Printf("Fetched %d viral stories from r/%s", len(redditResp.Data.Children), subreddit)
-
-		// Rate limiting - be respectful to Reddit API
-		time.Sleep(2 * time.Second)
+		stories = append(stories, ViralStory{
+			ID:          "reddit_" + post.Data.ID,
+			Title:       post.Data.Title,
+			Description: truncateText(post.Data.Selftext, 300),
+			URL:         "https://reddit.com" + post.Data.Permalink,
+			ImageURL:    imageURL,
+			Source:      "reddit",
+			SourceID:    post.Data.ID,
+			Author:      post.Data.Author,
+			Category:    post.Data.Subreddit,
+			PublishedAt: time.Unix(int64(post.Data.Created), 0),
+			FetchedAt:   time.Now(),
+			Upvotes:     post.Data.Score,
+			Comments:    post.Data.NumComments,
+			Shares:      0,
+			Engagement:  float64(post.Data.Score+post.Data.NumComments) / 100.0,
+			NSFW:        post.Data.Over18,
+			Spoiler:     post.Data.Spoiler,
+		})
 	}
+	return stories
+}
 
-	return allStories, nil
+const hnAPI = "https://hacker-news.firebaseio.com/v0"
+
+var (
+	hnStoryLimit = getenvInt("VIRAL_HN_LIMIT", 30)
+	hnWorkers    = getenvInt("VIRAL_HN_WORKERS", 8)
+	// Shorter than the 20-minute background refresh, so each scheduled refresh
+	// re-reads scores; the cache only spares manual refreshes in between
+	hnItemTTL = time.Duration(getenvInt("VIRAL_HN_ITEM_TTL_MINUTES", 15)) * time.Minute
+)
+
+// hnItemCache keeps fetched HN items by ID. Entries expire after the TTL or
+// as soon as HN lists the item in updates.json. updates.json only covers the
+// last few minutes, so the TTL is what bounds how stale a score can get.
+type hnItemCache struct {
+	mu      sync.Mutex
+	items   map[int]HackerNewsItem
+	fetched map[int]time.Time
 }
 
-// FetchHackerNewsViral fetches top stories from HackerNews
-func FetchHackerNewsViral() ([]ViralStory, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
-	var allStories []ViralStory
+var hnCache = &hnItemCache{items: map[int]HackerNewsItem{}, fetched: map[int]time.Time{}}
 
-	// Get top story IDs
-	resp, err := client.Get("https://hacker-news.firebaseio.com/v0/topstories.json")
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch HN top stories: %w", err)
+func (c *hnItemCache) get(id int) (HackerNewsItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok || time.Since(c.fetched[id]) > hnItemTTL {
+		return HackerNewsItem{}, false
+	}
+	return item, true
+}
+
+func (c *hnItemCache) put(item HackerNewsItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[item.ID] = item
+	c.fetched[item.ID] = time.Now()
+}
+
+// invalidate drops changed items and anything past its TTL
+func (c *hnItemCache) invalidate(changed []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range changed {
+		delete(c.items, id)
+		delete(c.fetched, id)
+	}
+	for id, at := range c.fetched {
+		if time.Since(at) > hnItemTTL {
+			delete(c.items, id)
+			delete(c.fetched, id)
+		}
 	}
-	defer resp.Body.Close()
+}
 
+// FetchHackerNewsViral fetches top stories from HackerNews
+func FetchHackerNewsViral(ctx context.Context) ([]ViralStory, error) {
 	var storyIDs []int
-	if err := json.NewDecoder(resp.Body).Decode(&storyIDs); err != nil {
-		return nil, fmt.Errorf("failed to decode HN story IDs: %w", err)
+	if err := getJSON(ctx, hnAPI+"/topstories.json", &storyIDs); err != nil {
+		return nil, fmt.Errorf("failed to fetch HN top stories: %w", err)
 	}
 
-	// Fetch details for top 20 stories
-	limit := 20
-	if len(storyIDs) < limit {
-		limit = len(storyIDs)
+	var updates struct {
+		Items []int `json:"items"`
 	}
+	if err := getJSON(ctx, hnAPI+"/updates.json", &updates); err != nil {
+		// Without the change list the TTL alone bounds staleness
+		log.Printf("Error fetching HN updates, relying on cache TTL: %v", err)
+	}
+	hnCache.invalidate(updates.Items)
 
-	for i := 0; i < limit; i++ {
-		storyID := storyIDs[i]
+	if len(storyIDs) > hnStoryLimit {
+		storyIDs = storyIDs[:hnStoryLimit]
+	}
 
-		itemURL := fmt.Sprintf("https://hacker-news.firebaseio.com/v0/item/%d.json", storyID)
-		resp, err := client.Get(itemURL)
-		if err != nil {
-			log.Printf("Error fetching HN item %d: %v", storyID, err)
-			continue
+	var cached atomic.Int32
+	items, err := fetchEach(ctx, storyIDs, hnWorkers, func(ctx context.Context, id int) (HackerNewsItem, bool, error) {
+		if item, ok := hnCache.get(id); ok {
+			cached.Add(1)
+			return item, true, nil
 		}
-
 		var item HackerNewsItem
-		if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
-			log.Printf("Error decoding HN item %d: %v", storyID, err)
-			resp.Body.Close()
-			continue
+		if err := getJSON(ctx, fmt.Sprintf("%s/item/%d.json", hnAPI, id), &item); err != nil {
+			log.Printf("Error fetching HN item %d: %v", id, err)
+			return item, false, err
 		}
-		resp.Body.Close()
+		hnCache.put(item)
+		return item, true, nil
+	})
+	if err != nil && len(items) == 0 {
+		return nil, fmt.Errorf("failed to fetch HN items: %w", err)
+	}
 
+	var allStories []ViralStory
+	for _, item := range items {
 		// Skip jobs, polls, etc - only want stories
 		if item.Type != "story" || item.URL == "" {
 			continue
 		}
 
-		// Calculate viral score
-		viralScore := calculateViralScore(item.Score, item.Descendants, 0)
-
-		story := ViralStory{
+		allStories = append(allStories, ViralStory{
 			ID:          "hn_" + strconv.Itoa(item.ID),
 			Title:       item.Title,
 			Description: "Top story on Hacker News",
@@ -153,37 +187,17 @@ func FetchHackerNewsViral() ([]ViralStory, error) {
 			Category:    "technology",
 			PublishedAt: time.Unix(item.Time, 0),
 			FetchedAt:   time.Now(),
-			ViralScore:  viralScore,
 			Upvotes:     item.Score,
 			Comments:    item.Descendants,
 			Shares:      0,
 			Engagement:  float64(item.Score+item.Descendants) / 50.0,
-		}
-
-		allStories = append(allStories, story)
-
-		// Rate limiting
-		time.Sleep(100 * time.Millisecond)
+		})
 	}
 
-	log.Printf("Fetched %d viral stories from HackerNews", len(allStories))
+	log.Printf("Fetched %d viral stories from HackerNews (%d items from cache)", len(allStories), cached.Load())
 	return allStories, nil
 }
 
-// calculateViralScore computes a viral score based on engagement metrics
-func calculateViralScore(upvotes, comments, shares int) int {
-	// Weighted formula: upvotes are worth more, but comments show engagement
-	score := (upvotes * 3) + (comments * 5) + (shares * 2)
-
-	// Normalize to 0-100 scale
-	normalized := score / 100
-	if normalized > 100 {
-		normalized = 100
-	}
-
-	return normalized
-}
-
 // truncateText truncates text to maxLen characters
 func truncateText(text string, maxLen int) string {
 	text = strings.TrimSpace(text)
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var providerClient = &http.Client{Timeout: 10 * time.Second}
+
+// Minimum spacing between requests to one host. Reddit throttles anonymous
+// clients hard; the HN Firebase API is happy with many small requests.
+var hostIntervals = map[string]time.Duration{
+	"old.reddit.com":             2 * time.Second,
+	"www.reddit.com":             2 * time.Second,
+	"hacker-news.firebaseio.com": 20 * time.Millisecond,
+}
+
+const defaultHostInterval = 200 * time.Millisecond
+
+// hostLimiter hands out request slots per host at a fixed spacing
+type hostLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+var limiter = &hostLimiter{next: map[string]time.Time{}}
+
+// wait blocks until host may be called again or ctx is done
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	interval, ok := hostIntervals[host]
+	if !ok {
+		interval = defaultHostInterval
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// getJSON fetches rawURL, respecting the host's rate limit, and decodes the
+// body into v. headers override the defaults.
+func getJSON(ctx context.Context, rawURL string, v interface{}, headers ...map[string]string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if err := limiter.wait(ctx, parsed.Host); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "ScrollfeedBot/1.0 (+https://scrollfeed.app)")
+	req.Header.Set("Accept", "application/json")
+	for _, h := range headers {
+		for k, v := range h {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := providerClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("%s returned status %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// fetchEach runs fetch for every input on a bounded pool of workers and
+// returns the results in input order. Failed inputs are left out, so one
+// bad item never costs the whole batch; the error is only set when every
+// input failed or ctx was cancelled.
+func fetchEach[In, Out any](ctx context.Context, inputs []In, workers int, fetch func(context.Context, In) (Out, bool, error)) ([]Out, error) {
+	type result struct {
+		value Out
+		ok    bool
+		err   error
+	}
+	results := make([]result, len(inputs))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				value, ok, err := fetch(ctx, inputs[i])
+				results[i] = result{value: value, ok: ok, err: err}
+			}
+		}()
+	}
+
+feed:
+	for i := range inputs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	out := make([]Out, 0, len(inputs))
+	var lastErr error
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			lastErr = r.err
+			failed++
+			continue
+		}
+		if r.ok {
+			out = append(out, r.value)
+		}
+	}
+	if ctx.Err() != nil {
+		return out, ctx.Err()
+	}
+	if failed > 0 && failed == len(inputs) {
+		return nil, lastErr
+	}
+	return out, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +35,11 @@ func main() {
 	log.Println("MongoDB connection successful")
 	mongoClient = client
 	viralCollection = client.Database("viraldb").Collection("stories")
+	takedownsCollection = client.Database("viraldb").Collection("takedowns")
+	snapshotsCollection = client.Database("viraldb").Collection("score_snapshots")
+	ensureIndexes(ctx)
+	viralSources = loadSources()
+	blocklist = loadBlocklist()
 
 	// Start background refresh
 	go backgroundRefresh(ctx)
@@ -57,11 +63,13 @@ func main() {
 		api.GET("/refresh", triggerRefresh)
 		api.GET("/sources", getSources)
 		api.GET("/categories", getCategories)
+		registerAdminRoutes(api.Group("/admin", adminAuth()))
 	}
 
 	// Also keep the original routes for direct access
 	r.GET("/viral/trending", getTrendingViral)
 	r.GET("/viral/refresh", triggerRefresh)
+	registerAdminRoutes(r.Group("/viral/admin", adminAuth()))
 
 	log.Println("Starting viral service on :8080")
 	r.Run(":8080")
@@ -75,6 +83,13 @@ func getenv(key, fallback string) string {
 	return v
 }
 
+func getenvInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
 func backgroundRefresh(ctx context.Context) {
 	// Initial refresh on startup
 	log.Println("Performing initial viral stories refresh...")
@@ -98,24 +113,11 @@ func backgroundRefresh(ctx context.Context) {
 func refreshViral(ctx context.Context) {
 	log.Println("Starting viral stories fetch...")
 
-	// Fetch from Reddit
-	redditStories, err1 := FetchRedditViral()
-	if err1 != nil {
-		log.Printf("Error fetching Reddit viral stories: %v", err1)
-	} else {
-		log.Printf("Fetched %d stories from Reddit", len(redditStories))
-	}
+	// Bound the whole refresh so a slow source can't stall the next cycle
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
 
-	// Fetch from HackerNews
-	hnStories, err2 := FetchHackerNewsViral()
-	if err2 != nil {
-		log.Printf("Error fetching HackerNews viral stories: %v", err2)
-	} else {
-		log.Printf("Fetched %d stories from HackerNews", len(hnStories))
-	}
-
-	// Combine all stories
-	allStories := append(redditStories, hnStories...)
+	allStories := fetchAll(ctx)
 
 	// Deduplicate
 	stories := deduplicateStories(allStories)
@@ -125,7 +127,9 @@ func refreshViral(ctx context.Context) {
 		return
 	}
 
-	// Sort by viral score
+	scoreStories(ctx, stories, time.Now())
+
+	// Sort by decayed viral score
 	sort.Slice(stories, func(i, j int) bool {
 		return stories[i].ViralScore > stories[j].ViralScore
 	})
@@ -140,6 +144,8 @@ func refreshViral(ctx context.Context) {
 		return
 	}
 
+	moderateStories(ctx, stories)
+
 	// Clear old stories
 	_, err := viralCollection.DeleteMany(ctx, bson.M{})
 	if err != nil {
@@ -182,8 +188,21 @@ func getTrendingViral(c *gin.Context) {
 		}
 	}
 
-	// Build filter
-	filter := bson.M{}
+	// Build filter; moderated stories are never served
+	filter := bson.M{"moderation.hidden": bson.M{"$ne": true}}
+	switch c.DefaultQuery("nsfw", "exclude") {
+	case "exclude":
+		filter["nsfw"] = bson.M{"$ne": true}
+	case "only":
+		filter["nsfw"] = true
+	case "include":
+	default:
+		c.JSON(400, gin.H{"error": "nsfw must be exclude, include or only"})
+		return
+	}
+	if c.Query("spoilers") == "exclude" {
+		filter["spoiler"] = bson.M{"$ne": true}
+	}
 	if source != "" {
 		filter["source"] = source
 	}
@@ -191,9 +210,20 @@ func getTrendingViral(c *gin.Context) {
 		filter["category"] = category
 	}
 
-	// Find options: sort by viral score descending
+	// hot decays points by age, top is raw points, rising is points per
+	// hour among recent stories
+	sortBy := c.DefaultQuery("sort", "hot")
+	sortField, ok := sortFields[sortBy]
+	if !ok {
+		c.JSON(400, gin.H{"error": "sort must be hot, top or rising"})
+		return
+	}
+	if sortBy == "rising" {
+		filter["published_at"] = bson.M{"$gte": time.Now().Add(-risingMaxAge)}
+	}
+
 	opts := options.Find().
-		SetSort(bson.D{{Key: "viral_score", Value: -1}}).
+		SetSort(bson.D{{Key: sortField, Value: -1}, {Key: "_id", Value: 1}}).
 		SetLimit(int64(limitInt))
 
 	cursor, err := viralCollection.Find(ctx, filter, opts)
@@ -210,11 +240,20 @@ func getTrendingViral(c *gin.Context) {
 		return
 	}
 
-	log.Printf("Serving %d viral stories (source: %s, category: %s)", len(stories), source, category)
+	now := time.Now()
+	for i := range stories {
+		stories[i].RankReason = rankReason(stories[i], sortBy, now)
+	}
+
+	stories, personalized := personalizeStories(c, stories)
+
+	log.Printf("Serving %d viral stories (source: %s, category: %s, sort: %s)", len(stories), source, category, sortBy)
 
 	c.JSON(200, gin.H{
-		"count":   len(stories),
-		"stories": stories,
+		"count":        len(stories),
+		"stories":      stories,
+		"sort":         sortBy,
+		"personalized": personalized,
 	})
 }
 
@@ -231,7 +270,10 @@ func triggerRefresh(c *gin.Context) {
 }
 
 func getSources(c *gin.Context) {
-	sources := []string{"reddit", "hackernews"}
+	sources := []gin.H{}
+	for _, s := range viralSources {
+		sources = append(sources, gin.H{"name": s.Name(), "scale": s.Scale()})
+	}
 	c.JSON(200, gin.H{
 		"sources": sources,
 	})
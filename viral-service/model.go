@@ -9,7 +9,7 @@ type ViralStory struct {
 	Description string    `json:"description" bson:"description"`
 	URL         string    `json:"url" bson:"url"`
 	ImageURL    string    `json:"image_url" bson:"image_url"`
-	Source      string    `json:"source" bson:"source"` // "reddit", "hackernews", "twitter"
+	Source      string    `json:"source" bson:"source"` // "reddit", "hackernews", "lobsters", "mastodon" or a JSON source name
 	SourceID    string    `json:"source_id" bson:"source_id"`
 	Author      string    `json:"author" bson:"author"`
 	Category    string    `json:"category" bson:"category"`
@@ -22,6 +22,20 @@ type ViralStory struct {
 	Comments   int     `json:"comments" bson:"comments"`
 	Shares     int     `json:"shares" bson:"shares"`
 	Engagement float64 `json:"engagement" bson:"engagement"`
+
+	// Time-decayed ranking, recomputed every refresh
+	Points     int     `json:"points" bson:"points"`       // Engagement on the common cross-source scale
+	HotScore   float64 `json:"hot_score" bson:"hot_score"` // Points decayed by age
+	Velocity   float64 `json:"velocity" bson:"velocity"`   // Points gained per hour
+	RankReason string  `json:"rank_reason,omitempty" bson:"-"`
+
+	// Content flags from the source and the moderation outcome
+	NSFW       bool        `json:"nsfw" bson:"nsfw"`
+	Spoiler    bool        `json:"spoiler" bson:"spoiler"`
+	Moderation *Moderation `json:"moderation,omitempty" bson:"moderation,omitempty"`
+
+	// Set on responses when explain=true; never stored
+	Personalization *Personalization `json:"personalization,omitempty" bson:"-"`
 }
 
 // RedditPost represents a post from Reddit API
@@ -38,6 +52,8 @@ type RedditPost struct {
 		NumComments int     `json:"num_comments"`
 		Created     float64 `json:"created_utc"`
 		ID          string  `json:"id"`
+		Over18      bool    `json:"over_18"`
+		Spoiler     bool    `json:"spoiler"`
 	} `json:"data"`
 }
 
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Moderation records why a story is flagged or hidden. NSFW and spoiler
+// are flags clients filter on; blocklist hits and takedowns hide the story.
+type Moderation struct {
+	Hidden    bool      `json:"hidden" bson:"hidden"`
+	Reasons   []string  `json:"reasons,omitempty" bson:"reasons,omitempty"`
+	CheckedAt time.Time `json:"checked_at" bson:"checked_at"`
+}
+
+// Takedown is a manual removal by story ID, which is stable per source post
+type Takedown struct {
+	StoryID   string    `json:"story_id" bson:"_id"`
+	URL       string    `json:"url,omitempty" bson:"url,omitempty"`
+	Reason    string    `json:"reason" bson:"reason"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Blocklist hides stories whose title or description contains a keyword or
+// whose link or image is on a blocked domain (subdomains included)
+type Blocklist struct {
+	Keywords []string `json:"keywords"`
+	Domains  []string `json:"domains"`
+
+	patterns []*regexp.Regexp
+}
+
+var takedownsCollection *mongo.Collection
+var blocklist *Blocklist
+
+// loadBlocklist reads MODERATION_BLOCKLIST_FILE (JSON with keywords and
+// domains) and adds MODERATION_BLOCKED_KEYWORDS and MODERATION_BLOCKED_DOMAINS
+func loadBlocklist() *Blocklist {
+	b := &Blocklist{}
+	if path := getenv("MODERATION_BLOCKLIST_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Warning: Failed to read blocklist %s: %v", path, err)
+		} else if err := json.Unmarshal(data, b); err != nil {
+			log.Printf("Warning: Failed to parse blocklist %s: %v", path, err)
+		}
+	}
+	b.Keywords = append(b.Keywords, splitList(getenv("MODERATION_BLOCKED_KEYWORDS", ""))...)
+	b.Domains = append(b.Domains, splitList(getenv("MODERATION_BLOCKED_DOMAINS", ""))...)
+
+	for _, keyword := range b.Keywords {
+		b.patterns = append(b.patterns, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(keyword)+`\b`))
+	}
+	log.Printf("Moderation blocklist: %d keywords, %d domains", len(b.Keywords), len(b.Domains))
+	return b
+}
+
+// check returns the blocklist reasons matching text and URLs
+func (b *Blocklist) check(text string, urls ...string) []string {
+	reasons := []string{}
+	for i, pattern := range b.patterns {
+		if pattern.MatchString(text) {
+			reasons = append(reasons, "blocked_keyword:"+b.Keywords[i])
+		}
+	}
+	for _, raw := range urls {
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		for _, domain := range b.Domains {
+			domain = strings.ToLower(domain)
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				reasons = append(reasons, "blocked_domain:"+domain)
+			}
+		}
+	}
+	return reasons
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// moderate evaluates a story against flags, the blocklist and a takedown
+func moderate(s ViralStory, takedown *Takedown) *Moderation {
+	mod := &Moderation{CheckedAt: time.Now()}
+	if s.NSFW {
+		mod.Reasons = append(mod.Reasons, "nsfw")
+	}
+	if s.Spoiler {
+		mod.Reasons = append(mod.Reasons, "spoiler")
+	}
+	if blocked := blocklist.check(s.Title+"\n"+s.Description, s.URL, s.ImageURL); len(blocked) > 0 {
+		mod.Hidden = true
+		mod.Reasons = append(mod.Reasons, blocked...)
+	}
+	if takedown != nil {
+		mod.Hidden = true
+		mod.Reasons = append(mod.Reasons, "takedown:"+takedown.Reason)
+	}
+	return mod
+}
+
+// moderateStories sets Moderation on a refresh batch
+func moderateStories(ctx context.Context, stories []ViralStory) {
+	takedowns := map[string]*Takedown{}
+	cursor, err := takedownsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		log.Printf("Error loading takedowns: %v", err)
+	} else {
+		var all []Takedown
+		if err := cursor.All(ctx, &all); err != nil {
+			log.Printf("Error decoding takedowns: %v", err)
+		}
+		for i := range all {
+			takedowns[all[i].StoryID] = &all[i]
+		}
+	}
+
+	hidden := 0
+	for i := range stories {
+		stories[i].Moderation = moderate(stories[i], takedowns[stories[i].ID])
+		if stories[i].Moderation.Hidden {
+			hidden++
+			log.Printf("Hiding story %s: %s", stories[i].ID, strings.Join(stories[i].Moderation.Reasons, ", "))
+		}
+	}
+	if hidden > 0 {
+		log.Printf("Moderation hid %d of %d stories", hidden, len(stories))
+	}
+}
+
+// adminAuth guards the admin API with a shared token from ADMIN_TOKEN; the
+// API is off when no token is configured
+func adminAuth() gin.HandlerFunc {
+	token := getenv("ADMIN_TOKEN", "")
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(503, gin.H{"error": "admin API disabled"})
+			return
+		}
+		if c.GetHeader("X-Admin-Token") != token {
+			c.AbortWithStatusJSON(401, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func registerAdminRoutes(r gin.IRoutes) {
+	r.GET("/takedowns", listTakedowns)
+	r.POST("/takedowns", createTakedown)
+	r.DELETE("/takedowns/:id", deleteTakedown)
+	r.GET("/hidden", listHiddenStories)
+}
+
+func listTakedowns(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := takedownsCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		log.Printf("DB Find error: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+	takedowns := []Takedown{}
+	if err := cursor.All(ctx, &takedowns); err != nil {
+		log.Printf("DB cursor.All error: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+	c.JSON(200, gin.H{"takedowns": takedowns})
+}
+
+// createTakedown hides a story immediately and on every later refresh. The
+// story doesn't have to be stored yet, so posts can be blocked ahead of time.
+func createTakedown(c *gin.Context) {
+	var req struct {
+		ID     string `json:"id" binding:"required"`
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "id and reason are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	takedown := Takedown{StoryID: req.ID, Reason: req.Reason, CreatedAt: time.Now()}
+	var story ViralStory
+	err := viralCollection.FindOne(ctx, bson.M{"_id": req.ID}).Decode(&story)
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("DB FindOne error: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+	found := err == nil
+	if found {
+		takedown.URL = story.URL
+	}
+
+	if _, err := takedownsCollection.ReplaceOne(ctx, bson.M{"_id": takedown.StoryID}, takedown, options.Replace().SetUpsert(true)); err != nil {
+		log.Printf("Error storing takedown: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+	if found {
+		if err := applyModeration(ctx, story, &takedown); err != nil {
+			c.JSON(500, gin.H{"error": "database error"})
+			return
+		}
+	}
+
+	log.Printf("Story %s taken down: %s", req.ID, req.Reason)
+	c.JSON(201, gin.H{"takedown": takedown, "stored": found})
+}
+
+// deleteTakedown lifts a takedown; the story is re-evaluated against the
+// remaining rules rather than simply unhidden
+func deleteTakedown(c *gin.Context) {
+	id := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	var takedown Takedown
+	if err := takedownsCollection.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&takedown); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(404, gin.H{"error": "takedown not found"})
+			return
+		}
+		log.Printf("Error deleting takedown: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+
+	var story ViralStory
+	err := viralCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&story)
+	if err == nil {
+		err = applyModeration(ctx, story, nil)
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		log.Printf("Error re-moderating story %s: %v", id, err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "takedown removed", "takedown": takedown})
+}
+
+func applyModeration(ctx context.Context, story ViralStory, takedown *Takedown) error {
+	mod := moderate(story, takedown)
+	if _, err := viralCollection.UpdateOne(ctx, bson.M{"_id": story.ID}, bson.M{"$set": bson.M{"moderation": mod}}); err != nil {
+		log.Printf("Error updating story moderation: %v", err)
+		return err
+	}
+	return nil
+}
+
+func listHiddenStories(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := viralCollection.Find(ctx, bson.M{"moderation.hidden": true})
+	if err != nil {
+		log.Printf("DB Find error: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+	stories := []ViralStory{}
+	if err := cursor.All(ctx, &stories); err != nil {
+		log.Printf("DB cursor.All error: %v", err)
+		c.JSON(500, gin.H{"error": "database error"})
+		return
+	}
+	c.JSON(200, gin.H{"count": len(stories), "stories": stories})
+}
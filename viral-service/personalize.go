@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Personalisation is delegated to analytics-service, which owns the session history
+var (
+	personalizeURL     = strings.TrimRight(getenv("PERSONALIZATION_URL", "http://analytics-service:8080"), "/")
+	personalizeEnabled = getenv("PERSONALIZATION_ENABLED", "true") != "false"
+	personalizeClient  = &http.Client{Timeout: 300 * time.Millisecond}
+)
+
+// RankCandidate is a story sent to analytics-service for ranking
+type RankCandidate struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Source      string    `json:"source,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	PublishedAt time.Time `json:"published_at,omitempty"`
+	Popularity  float64   `json:"popularity"`
+}
+
+// Personalization explains where a story landed for the session
+type Personalization struct {
+	Score       float64 `json:"score"`
+	Explanation struct {
+		Recency    float64  `json:"recency"`
+		Popularity float64  `json:"popularity"`
+		Affinity   float64  `json:"affinity"`
+		Reasons    []string `json:"reasons,omitempty"`
+	} `json:"explanation"`
+}
+
+type rankResponse struct {
+	Personalized bool `json:"personalized"`
+	Rankings     []struct {
+		ID string `json:"id"`
+		Personalization
+	} `json:"rankings"`
+}
+
+// personalizeStories re-ranks stories for the caller's session unless the
+// request passed personalize=false. Any failure keeps the viral score order.
+func personalizeStories(c *gin.Context, stories []ViralStory) ([]ViralStory, bool) {
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		sessionID = c.GetHeader("X-Session-ID")
+	}
+	if !personalizeEnabled || c.Query("personalize") == "false" || sessionID == "" || len(stories) < 2 {
+		return stories, false
+	}
+
+	candidates := make([]RankCandidate, 0, len(stories))
+	byID := make(map[string]ViralStory, len(stories))
+	for _, story := range stories {
+		byID[story.ID] = story
+		candidates = append(candidates, RankCandidate{
+			ID:          story.ID,
+			Title:       story.Title,
+			Source:      story.Source,
+			Category:    story.Category,
+			PublishedAt: story.PublishedAt,
+			Popularity:  float64(story.ViralScore) / 100.0,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Second)
+	defer cancel()
+
+	result, err := rankStories(ctx, sessionID, candidates)
+	if err != nil {
+		log.Printf("Personalisation skipped: %v", err)
+		return stories, false
+	}
+	if len(result.Rankings) != len(stories) {
+		return stories, false
+	}
+
+	explain := c.Query("explain") == "true"
+	ranked := make([]ViralStory, 0, len(stories))
+	for _, ranking := range result.Rankings {
+		story, ok := byID[ranking.ID]
+		if !ok {
+			return stories, false
+		}
+		if explain {
+			p := ranking.Personalization
+			story.Personalization = &p
+		}
+		ranked = append(ranked, story)
+	}
+	return ranked, result.Personalized
+}
+
+func rankStories(ctx context.Context, sessionID string, candidates []RankCandidate) (*rankResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"session_id": sessionID,
+		"candidates": candidates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", personalizeURL+"/api/v1/personalize/rank", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := personalizeClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rank request returned status %d", resp.StatusCode)
+	}
+
+	var result rankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode rank response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// urlID derives a stable story ID for sources without their own IDs
+func urlID(prefix, url string) string {
+	sum := sha1.Sum([]byte(url))
+	return prefix + "_" + hex.EncodeToString(sum[:8])
+}
+
+// LobstersSource fetches the Lobsters hottest page
+type LobstersSource struct{}
+
+type lobstersStory struct {
+	ShortID      string          `json:"short_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+	Title        string          `json:"title"`
+	URL          string          `json:"url"`
+	Score        int             `json:"score"`
+	CommentCount int             `json:"comment_count"`
+	Description  string          `json:"description_plain"`
+	CommentsURL  string          `json:"comments_url"`
+	Submitter    json.RawMessage `json:"submitter_user"` // A name, or an object in older API versions
+	Tags         []string        `json:"tags"`
+}
+
+func (s *LobstersSource) Name() string   { return "lobsters" }
+func (s *LobstersSource) Scale() float64 { return lobstersScale }
+
+func (s *LobstersSource) Fetch(ctx context.Context) ([]ViralStory, error) {
+	var items []lobstersStory
+	if err := getJSON(ctx, "https://lobste.rs/hottest.json", &items); err != nil {
+		return nil, fmt.Errorf("failed to fetch Lobsters: %w", err)
+	}
+
+	stories := []ViralStory{}
+	for _, item := range items {
+		url := item.URL
+		if url == "" {
+			url = item.CommentsURL // Text posts link to their discussion
+		}
+		category := "technology"
+		if len(item.Tags) > 0 {
+			category = item.Tags[0]
+		}
+
+		stories = append(stories, ViralStory{
+			ID:          "lobsters_" + item.ShortID,
+			Title:       item.Title,
+			Description: truncateText(item.Description, 300),
+			URL:         url,
+			Source:      s.Name(),
+			SourceID:    item.ShortID,
+			Author:      lobstersSubmitter(item.Submitter),
+			Category:    category,
+			PublishedAt: item.CreatedAt,
+			FetchedAt:   time.Now(),
+			Upvotes:     item.Score,
+			Comments:    item.CommentCount,
+			Engagement:  float64(item.Score+item.CommentCount) / 10.0,
+		})
+	}
+	return stories, nil
+}
+
+func lobstersSubmitter(raw json.RawMessage) string {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		return name
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	json.Unmarshal(raw, &user)
+	return user.Username
+}
+
+// MastodonSource fetches links trending on a Mastodon instance. Trends carry
+// no votes; the number of accounts sharing a link stands in for upvotes and
+// total posts for shares.
+type MastodonSource struct {
+	Instance string
+}
+
+type mastodonLink struct {
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	Image        string `json:"image"`
+	History      []struct {
+		Day      string `json:"day"` // Unix time as a string
+		Uses     string `json:"uses"`
+		Accounts string `json:"accounts"`
+	} `json:"history"`
+}
+
+func (s *MastodonSource) Name() string   { return "mastodon" }
+func (s *MastodonSource) Scale() float64 { return mastodonScale }
+
+func (s *MastodonSource) Fetch(ctx context.Context) ([]ViralStory, error) {
+	var links []mastodonLink
+	url := fmt.Sprintf("https://%s/api/v1/trends/links?limit=20", strings.TrimPrefix(s.Instance, "https://"))
+	if err := getJSON(ctx, url, &links); err != nil {
+		return nil, fmt.Errorf("failed to fetch Mastodon trends: %w", err)
+	}
+
+	now := time.Now()
+	stories := []ViralStory{}
+	for _, link := range links {
+		// History is newest first; the last two days make up the trend
+		var accounts, uses int
+		published := now
+		for i, day := range link.History {
+			if i >= 2 {
+				break
+			}
+			a, _ := strconv.Atoi(day.Accounts)
+			u, _ := strconv.Atoi(day.Uses)
+			accounts += a
+			uses += u
+			if unix, err := strconv.ParseInt(day.Day, 10, 64); err == nil && u > 0 {
+				published = time.Unix(unix, 0)
+			}
+		}
+
+		author := link.AuthorName
+		if author == "" {
+			author = link.ProviderName
+		}
+
+		stories = append(stories, ViralStory{
+			ID:          urlID("mastodon", link.URL),
+			Title:       link.Title,
+			Description: truncateText(link.Description, 300),
+			URL:         link.URL,
+			ImageURL:    link.Image,
+			Source:      s.Name(),
+			SourceID:    link.URL,
+			Author:      author,
+			Category:    "trending",
+			PublishedAt: published,
+			FetchedAt:   now,
+			Upvotes:     accounts,
+			Shares:      uses,
+			Engagement:  float64(accounts+uses) / 20.0,
+		})
+	}
+	return stories, nil
+}
+
+// JSONSourceConfig describes a JSON endpoint and where the story fields
+// live in each item. Paths are dot-separated keys, e.g. "data.children"
+// or "stats.likes".
+//
+//	[{"name": "example", "url": "https://example.com/top.json",
+//	  "items": "data.items", "scale": 1.5, "category": "news",
+//	  "fields": {"id": "id", "title": "headline", "url": "link",
+//	             "upvotes": "stats.likes", "comments": "stats.replies",
+//	             "published_at": "created"}}]
+type JSONSourceConfig struct {
+	Name     string  `json:"name"`
+	URL      string  `json:"url"`
+	Items    string  `json:"items"` // Path to the item array; empty when the body is the array
+	Scale    float64 `json:"scale"`
+	Category string  `json:"category"`
+	Fields   struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		URL         string `json:"url"`
+		Description string `json:"description"`
+		Author      string `json:"author"`
+		Image       string `json:"image"`
+		Upvotes     string `json:"upvotes"`
+		Comments    string `json:"comments"`
+		Shares      string `json:"shares"`
+		PublishedAt string `json:"published_at"` // RFC 3339 or Unix seconds
+	} `json:"fields"`
+}
+
+// JSONSource is a config-driven source for any JSON list endpoint
+type JSONSource struct {
+	Config JSONSourceConfig
+}
+
+func (s *JSONSource) Name() string { return s.Config.Name }
+
+func (s *JSONSource) Scale() float64 {
+	if s.Config.Scale > 0 {
+		return s.Config.Scale
+	}
+	return 1.0
+}
+
+func (s *JSONSource) Fetch(ctx context.Context) ([]ViralStory, error) {
+	var body interface{}
+	if err := getJSON(ctx, s.Config.URL, &body); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", s.Config.Name, err)
+	}
+
+	items, ok := jsonPath(body, s.Config.Items).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: %q is not an array", s.Config.Name, s.Config.Items)
+	}
+
+	f := s.Config.Fields
+	category := s.Config.Category
+	if category == "" {
+		category = "general"
+	}
+	now := time.Now()
+	stories := []ViralStory{}
+	for _, item := range items {
+		title := jsonString(item, f.Title)
+		url := jsonString(item, f.URL)
+		if title == "" || url == "" {
+			continue
+		}
+		sourceID := jsonString(item, f.ID)
+		id := urlID(s.Config.Name, url)
+		if sourceID != "" {
+			id = s.Config.Name + "_" + sourceID
+		}
+		published := jsonTime(item, f.PublishedAt)
+		if published.IsZero() {
+			published = now
+		}
+		upvotes, comments, shares := jsonInt(item, f.Upvotes), jsonInt(item, f.Comments), jsonInt(item, f.Shares)
+
+		stories = append(stories, ViralStory{
+			ID:          id,
+			Title:       title,
+			Description: truncateText(jsonString(item, f.Description), 300),
+			URL:         url,
+			ImageURL:    jsonString(item, f.Image),
+			Source:      s.Config.Name,
+			SourceID:    sourceID,
+			Author:      jsonString(item, f.Author),
+			Category:    category,
+			PublishedAt: published,
+			FetchedAt:   now,
+			Upvotes:     upvotes,
+			Comments:    comments,
+			Shares:      shares,
+			Engagement:  float64(upvotes+comments+shares) / 100.0,
+		})
+	}
+	return stories, nil
+}
+
+// jsonPath walks a decoded JSON value along a dot-separated path
+func jsonPath(value interface{}, path string) interface{} {
+	if path == "" {
+		return value
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+func jsonString(item interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+	switch v := jsonPath(item, path).(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func jsonInt(item interface{}, path string) int {
+	if path == "" {
+		return 0
+	}
+	switch v := jsonPath(item, path).(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
+
+func jsonTime(item interface{}, path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	switch v := jsonPath(item, path).(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+		if unix, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(unix, 0)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Scoring follows Hacker News: points decay by (age+2)^gravity, so a fresh
+// story with modest points beats a week-old one with many
+var (
+	gravity          = getenvFloat("VIRAL_GRAVITY", 1.8)
+	risingMaxAge     = time.Duration(getenvFloat("VIRAL_RISING_MAX_AGE_HOURS", 24) * float64(time.Hour))
+	snapshotRetained = 7 * 24 * time.Hour
+	// Gaps shorter than this give noisy velocities; fall back to the average
+	minVelocityWindow = 10 * time.Minute
+)
+
+var snapshotsCollection *mongo.Collection
+
+// ScoreSnapshot is a story's engagement at one refresh
+type ScoreSnapshot struct {
+	StoryID  string    `bson:"story_id"`
+	Points   int       `bson:"points"`
+	Upvotes  int       `bson:"upvotes"`
+	Comments int       `bson:"comments"`
+	At       time.Time `bson:"at"`
+}
+
+// Sort orders accepted by the trending endpoint and the field each uses
+var sortFields = map[string]string{
+	"hot":    "hot_score",
+	"top":    "points",
+	"rising": "velocity",
+}
+
+func ensureIndexes(ctx context.Context) {
+	if _, err := snapshotsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "story_id", Value: 1}, {Key: "at", Value: -1}}},
+		{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(snapshotRetained.Seconds()))},
+	}); err != nil {
+		log.Printf("Warning: Failed to create snapshot indexes: %v", err)
+	}
+
+	indexes := []mongo.IndexModel{}
+	for _, field := range sortFields {
+		indexes = append(indexes, mongo.IndexModel{Keys: bson.D{{Key: field, Value: -1}}})
+	}
+	if _, err := viralCollection.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("Warning: Failed to create story indexes: %v", err)
+	}
+}
+
+// engagementPoints combines votes, comments and shares in the source's own
+// units; a comment takes more effort than a vote so it counts double. The
+// source scale then puts the result on the common points scale.
+func engagementPoints(source string, upvotes, comments, shares int) int {
+	scale, ok := sourceScales[source]
+	if !ok {
+		scale = 1.0
+	}
+	return int(math.Round(float64(upvotes+2*comments+shares) * scale))
+}
+
+// hotScore is the HN ranking formula
+func hotScore(points int, age time.Duration) float64 {
+	hours := math.Max(age.Hours(), 0)
+	return math.Max(float64(points-1), 0) / math.Pow(hours+2, gravity)
+}
+
+// scoreStories records a snapshot for each story, derives velocity from the
+// previous snapshot and sets the time-decayed scores. ViralScore becomes the
+// hot score scaled to 0-100 within the batch so existing consumers of
+// viral_score get decayed ordering too.
+func scoreStories(ctx context.Context, stories []ViralStory, now time.Time) {
+	previous := latestSnapshots(ctx, stories)
+
+	maxHot := 0.0
+	snapshots := make([]interface{}, 0, len(stories))
+	for i := range stories {
+		s := &stories[i]
+		s.Points = engagementPoints(s.Source, s.Upvotes, s.Comments, s.Shares)
+		age := now.Sub(s.PublishedAt)
+		s.HotScore = hotScore(s.Points, age)
+		if s.HotScore > maxHot {
+			maxHot = s.HotScore
+		}
+
+		// Points per hour since the last refresh, or since posting when
+		// there's no usable earlier snapshot
+		if prev, ok := previous[s.ID]; ok && now.Sub(prev.At) >= minVelocityWindow {
+			s.Velocity = float64(s.Points-prev.Points) / now.Sub(prev.At).Hours()
+		} else if age > 0 {
+			s.Velocity = float64(s.Points) / math.Max(age.Hours(), 1)
+		}
+
+		snapshots = append(snapshots, ScoreSnapshot{
+			StoryID:  s.ID,
+			Points:   s.Points,
+			Upvotes:  s.Upvotes,
+			Comments: s.Comments,
+			At:       now,
+		})
+	}
+
+	// With no points in the batch every story scores 0
+	for i := range stories {
+		stories[i].ViralScore = 0
+		if maxHot > 0 {
+			stories[i].ViralScore = int(math.Round(100 * stories[i].HotScore / maxHot))
+		}
+	}
+
+	if len(snapshots) > 0 {
+		if _, err := snapshotsCollection.InsertMany(ctx, snapshots); err != nil {
+			log.Printf("Error storing score snapshots: %v", err)
+		}
+	}
+}
+
+// latestSnapshots returns the most recent earlier snapshot per story
+func latestSnapshots(ctx context.Context, stories []ViralStory) map[string]ScoreSnapshot {
+	ids := make([]string, 0, len(stories))
+	for _, s := range stories {
+		ids = append(ids, s.ID)
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"story_id": bson.M{"$in": ids}}},
+		{"$sort": bson.M{"at": -1}},
+		{"$group": bson.M{"_id": "$story_id", "latest": bson.M{"$first": "$$ROOT"}}},
+		{"$replaceRoot": bson.M{"newRoot": "$latest"}},
+	}
+
+	latest := map[string]ScoreSnapshot{}
+	cursor, err := snapshotsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("Error loading score snapshots: %v", err)
+		return latest
+	}
+	var snapshots []ScoreSnapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		log.Printf("Error decoding score snapshots: %v", err)
+		return latest
+	}
+	for _, s := range snapshots {
+		latest[s.StoryID] = s
+	}
+	return latest
+}
+
+// rankReason explains a story's position for the requested sort
+func rankReason(s ViralStory, sortBy string, now time.Time) string {
+	age := now.Sub(s.PublishedAt).Hours()
+	switch sortBy {
+	case "top":
+		return fmt.Sprintf("top: %d points (%d upvotes, %d comments)", s.Points, s.Upvotes, s.Comments)
+	case "rising":
+		return fmt.Sprintf("rising: %+.0f points/hour, posted %.1fh ago", s.Velocity, age)
+	default:
+		return fmt.Sprintf("hot: %d points decayed over %.1fh (gravity %.1f)", s.Points, age, gravity)
+	}
+}
+
+func getenvFloat(key string, fallback float64) float64 {
+	if v, err := strconv.ParseFloat(getenv(key, ""), 64); err == nil {
+		return v
+	}
+	return fallback
+}
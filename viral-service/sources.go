@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+)
+
+// ViralSource is a provider of viral stories. Scale converts the source's
+// engagement into common points: raw counts differ by orders of magnitude
+// between sources (a front-page Reddit post has tens of thousands of votes,
+// a Lobsters one a few dozen), so each source is scaled so that a typical
+// front-page story lands near the same number of points.
+type ViralSource interface {
+	Name() string
+	Scale() float64
+	Fetch(ctx context.Context) ([]ViralStory, error)
+}
+
+// Reference scales, relative to a Hacker News front-page story
+const (
+	redditScale   = 0.05
+	hnScale       = 1.0
+	lobstersScale = 8.0
+	mastodonScale = 2.0
+)
+
+var viralSources []ViralSource
+
+// sourceScales maps source name to its points scale for scoring
+var sourceScales = map[string]float64{}
+
+// RedditSource fetches hot posts from the configured subreddits
+type RedditSource struct {
+	Subreddits []string
+}
+
+func (s *RedditSource) Name() string   { return "reddit" }
+func (s *RedditSource) Scale() float64 { return redditScale }
+func (s *RedditSource) Fetch(ctx context.Context) ([]ViralStory, error) {
+	return FetchRedditViral(ctx, s.Subreddits)
+}
+
+// HackerNewsSource fetches the Hacker News front page
+type HackerNewsSource struct{}
+
+func (s *HackerNewsSource) Name() string   { return "hackernews" }
+func (s *HackerNewsSource) Scale() float64 { return hnScale }
+func (s *HackerNewsSource) Fetch(ctx context.Context) ([]ViralStory, error) {
+	return FetchHackerNewsViral(ctx)
+}
+
+// loadSources builds the enabled sources from the environment:
+//
+//	VIRAL_SOURCES            comma-separated, default reddit,hackernews,lobsters,mastodon
+//	VIRAL_REDDIT_SUBREDDITS  default worldnews,news,technology
+//	VIRAL_MASTODON_INSTANCE  default mastodon.social
+//	VIRAL_JSON_SOURCES_FILE  JSON list of generic sources, see JSONSourceConfig
+func loadSources() []ViralSource {
+	sources := []ViralSource{}
+	for _, name := range splitList(getenv("VIRAL_SOURCES", "reddit,hackernews,lobsters,mastodon")) {
+		switch strings.ToLower(name) {
+		case "reddit":
+			sources = append(sources, &RedditSource{
+				Subreddits: splitList(getenv("VIRAL_REDDIT_SUBREDDITS", "worldnews,news,technology")),
+			})
+		case "hackernews":
+			sources = append(sources, &HackerNewsSource{})
+		case "lobsters":
+			sources = append(sources, &LobstersSource{})
+		case "mastodon":
+			sources = append(sources, &MastodonSource{Instance: getenv("VIRAL_MASTODON_INSTANCE", "mastodon.social")})
+		default:
+			log.Printf("Warning: Unknown viral source %q", name)
+		}
+	}
+
+	if path := getenv("VIRAL_JSON_SOURCES_FILE", ""); path != "" {
+		sources = append(sources, loadJSONSources(path)...)
+	}
+
+	names := []string{}
+	for _, s := range sources {
+		sourceScales[s.Name()] = s.Scale()
+		names = append(names, s.Name())
+	}
+	log.Printf("Viral sources enabled: %s", strings.Join(names, ", "))
+	return sources
+}
+
+func loadJSONSources(path string) []ViralSource {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Warning: Failed to read JSON sources %s: %v", path, err)
+		return nil
+	}
+	var configs []JSONSourceConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		log.Printf("Warning: Failed to parse JSON sources %s: %v", path, err)
+		return nil
+	}
+
+	sources := []ViralSource{}
+	for _, cfg := range configs {
+		if cfg.Name == "" || cfg.URL == "" || cfg.Fields.Title == "" {
+			log.Printf("Warning: Skipping JSON source without name, url or title field: %+v", cfg)
+			continue
+		}
+		sources = append(sources, &JSONSource{Config: cfg})
+	}
+	return sources
+}
+
+// fetchAll queries every source; one source failing doesn't stop the others
+func fetchAll(ctx context.Context) []ViralStory {
+	all := []ViralStory{}
+	for _, source := range viralSources {
+		stories, err := source.Fetch(ctx)
+		if err != nil {
+			// Keep whatever arrived before the failure
+			log.Printf("Error fetching %s viral stories (%d partial): %v", source.Name(), len(stories), err)
+		}
+		log.Printf("Fetched %d stories from %s", len(stories), source.Name())
+		all = append(all, stories...)
+	}
+	return all
+}